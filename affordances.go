@@ -0,0 +1,100 @@
+package gohalforms
+
+// AddLinkIf adds a new hyperlink to the HAL (Hypertext Application Language) resource under the specified relation,
+// but only if the condition holds.
+//
+// Parameters:
+//
+//	resource - A pointer to the Resource instance to which the link should be added.
+//	condition - Whether the link should be added.
+//	rel - The relation name under which the link will be stored.
+//	value - The Link instance to be added.
+//
+// Example:
+//
+//	// Only link to the invoice once the order has been paid for.
+//	halResource.AddLinkIf(order.Paid, "invoice", gohalforms.Link{Href: "/orders/1/invoice"})
+func (resource *Resource) AddLinkIf(condition bool, rel string, value Link) {
+	if condition {
+		resource.AddLink(rel, value)
+	}
+}
+
+// AddTemplateIf adds a new template to the HAL (Hypertext Application Language) resource under the specified name,
+// but only if the condition holds.
+//
+// Parameters:
+//
+//	resource - A pointer to the Resource instance to which the template should be added.
+//	condition - Whether the template should be added.
+//	rel - The relation name under which the template will be stored.
+//	value - The Template instance to be added.
+//
+// Example:
+//
+//	// Only allow the user to be edited by someone with permission to do so.
+//	halResource.AddTemplateIf(canEdit, "default", editTemplate)
+func (resource *Resource) AddTemplateIf(condition bool, rel string, value Template) {
+	if condition {
+		resource.AddTemplate(rel, value)
+	}
+}
+
+// AddAffordance adds a link and the template describing how to follow it to the HAL (Hypertext Application Language)
+// resource, both under the same relation. If the template has no target then it targets the link, and if it has no
+// title then it uses the title of the link.
+//
+// As with AddTemplate, if this is the only template of the resource then it is encoded under the key "default", as
+// required by HAL-FORMS, while the link stays under the relation. The target of the template still ties it to the link.
+//
+// Parameters:
+//
+//	resource - A pointer to the Resource instance to which the affordance should be added.
+//	rel - The relation name under which both the link and the template will be stored.
+//	link - The Link instance to be added.
+//	template - The Template instance to be added.
+//
+// Example:
+//
+//	// Describe how to cancel an order.
+//	halResource.AddAffordance("cancel",
+//	    gohalforms.Link{Href: "/orders/1/cancel", Title: "Cancel order"},
+//	    gohalforms.Template{Method: http.MethodPost},
+//	)
+func (resource *Resource) AddAffordance(rel string, link Link, template Template) {
+	if template.Target == "" {
+		template.Target = link.Href
+	}
+
+	if template.Title == "" {
+		template.Title = link.Title
+	}
+
+	resource.AddLink(rel, link)
+	resource.AddTemplate(rel, template)
+}
+
+// AddAffordanceIf adds a link and the template describing how to follow it to the HAL (Hypertext Application Language)
+// resource, as for AddAffordance, but only if the condition holds. This allows hypermedia that depends on the state of
+// the resource to be declared in one place.
+//
+// Parameters:
+//
+//	resource - A pointer to the Resource instance to which the affordance should be added.
+//	condition - Whether the affordance should be added.
+//	rel - The relation name under which both the link and the template will be stored.
+//	link - The Link instance to be added.
+//	template - The Template instance to be added.
+//
+// Example:
+//
+//	// Orders can only be cancelled while they are pending.
+//	halResource.AddAffordanceIf(order.Status == "pending", "cancel",
+//	    gohalforms.Link{Href: "/orders/1/cancel"},
+//	    gohalforms.Template{Method: http.MethodPost},
+//	)
+func (resource *Resource) AddAffordanceIf(condition bool, rel string, link Link, template Template) {
+	if condition {
+		resource.AddAffordance(rel, link, template)
+	}
+}
@@ -0,0 +1,91 @@
+package gohalforms_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/kinbiko/jsonassert"
+	"github.com/sazzer/gohalforms"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConditionalLinksAndTemplates(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(nil)
+	resource.AddLinkIf(true, "self", gohalforms.Link{Href: "/orders/1"})
+	resource.AddLinkIf(false, "invoice", gohalforms.Link{Href: "/orders/1/invoice"})
+	resource.AddTemplateIf(true, "edit", gohalforms.Template{Method: http.MethodPut})
+	resource.AddTemplateIf(false, "delete", gohalforms.Template{Method: http.MethodDelete})
+
+	encoded, err := gohalforms.Marshal(resource)
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"_links": {
+			"self": {"href": "/orders/1"}
+		},
+		"_templates": {
+			"default": {"method": "PUT", "properties": null}
+		}
+	}`)
+}
+
+func TestAddAffordance(t *testing.T) {
+	t.Parallel()
+
+	pending := true
+
+	resource := gohalforms.NewResource(nil)
+	resource.AddAffordanceIf(pending, "cancel",
+		gohalforms.Link{Href: "/orders/1/cancel", Title: "Cancel order"},
+		gohalforms.Template{Method: http.MethodPost},
+	)
+	resource.AddAffordanceIf(!pending, "reopen",
+		gohalforms.Link{Href: "/orders/1/reopen"},
+		gohalforms.Template{Method: http.MethodPost},
+	)
+	resource.AddAffordance("pay",
+		gohalforms.Link{Href: "/orders/1/pay", Title: "Pay"},
+		gohalforms.Template{Method: http.MethodPost, Target: "/payments", Title: "Pay for order"},
+	)
+
+	encoded, err := gohalforms.Marshal(resource)
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"_links": {
+			"cancel": {"href": "/orders/1/cancel", "title": "Cancel order"},
+			"pay": {"href": "/orders/1/pay", "title": "Pay"}
+		},
+		"_templates": {
+			"cancel": {"method": "POST", "target": "/orders/1/cancel", "title": "Cancel order", "properties": null},
+			"pay": {"method": "POST", "target": "/payments", "title": "Pay for order", "properties": null}
+		}
+	}`)
+}
+
+func TestAddAffordanceOnlyTemplate(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(nil)
+	resource.AddAffordance("cancel",
+		gohalforms.Link{Href: "/orders/1/cancel", Title: "Cancel order"},
+		gohalforms.Template{Method: http.MethodPost},
+	)
+
+	encoded, err := gohalforms.Marshal(resource)
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"_links": {
+			"cancel": {"href": "/orders/1/cancel", "title": "Cancel order"}
+		},
+		"_templates": {
+			"default": {"method": "POST", "target": "/orders/1/cancel", "title": "Cancel order", "properties": null}
+		}
+	}`)
+}
@@ -0,0 +1,126 @@
+package gohalforms
+
+import "context"
+
+// LinkAuthorizer decides whether the current request is allowed to see a link.
+//
+// Parameters:
+//
+//	ctx - The context of the current request, such as one carrying the authenticated user.
+//	rel - The relation under which the link is stored.
+//	link - The link itself.
+//
+// Returns:
+//
+//	true if the link should be kept; false if it should be removed.
+type LinkAuthorizer func(ctx context.Context, rel string, link Link) bool
+
+// TemplateAuthorizer decides whether the current request is allowed to see a template.
+//
+// Parameters:
+//
+//	ctx - The context of the current request, such as one carrying the authenticated user.
+//	name - The name under which the template is stored.
+//	template - The template itself.
+//
+// Returns:
+//
+//	true if the template should be kept; false if it should be removed.
+type TemplateAuthorizer func(ctx context.Context, name string, template Template) bool
+
+// Authorize returns a copy of the HAL (Hypertext Application Language) resource with the links and templates that the
+// current request is not allowed to see removed. This is applied recursively to embedded resources, including those
+// that are streamed, which are filtered as they are encoded. This allows every affordance to be declared once, and
+// then stripped down per request. A nil authorizer allows everything of its kind.
+//
+// Parameters:
+//
+//	ctx - The context of the current request, which is passed to the authorizers.
+//	links - The authorizer deciding which links to keep.
+//	templates - The authorizer deciding which templates to keep.
+//
+// Returns:
+//
+//	A new Resource containing only the authorized links and templates. The original resource is unchanged.
+//
+// Example:
+//
+//	// Only administrators may delete users.
+//	templates := func(ctx context.Context, name string, template gohalforms.Template) bool {
+//	    return template.Method != http.MethodDelete || isAdmin(ctx)
+//	}
+//
+//	// Send the resource with the disallowed templates removed.
+//	err := gohalforms.Send(w, halResource.Authorize(r.Context(), nil, templates))
+func (resource Resource) Authorize(ctx context.Context, links LinkAuthorizer, templates TemplateAuthorizer) Resource {
+	result := resource.withoutHypermedia()
+
+	for _, rel := range resource.links.keys(false) {
+		allowed := filterLinks(ctx, rel, resource.links.get(rel), links)
+		if len(allowed) > 0 {
+			result.links.set(rel, allowed)
+		}
+	}
+
+	for _, rel := range resource.embedded.keys(false) {
+		result.embedded.set(rel, resource.embedded.get(rel).authorize(ctx, links, templates))
+	}
+
+	for _, name := range resource.templates.keys(false) {
+		template := resource.templates.get(name)
+		if templates == nil || templates(ctx, name, template) {
+			result.templates.set(name, template)
+		}
+	}
+
+	return result
+}
+
+// withoutHypermedia returns a copy of the resource with no links, embedded resources or templates.
+func (resource Resource) withoutHypermedia() Resource {
+	resource.links = newRelset[links]()
+	resource.embedded = newRelset[resources]()
+	resource.templates = newRelset[Template]()
+
+	return resource
+}
+
+// filterLinks returns the links under a relation that the authorizer allows.
+func filterLinks(ctx context.Context, rel string, values links, authorizer LinkAuthorizer) links {
+	if authorizer == nil {
+		return values
+	}
+
+	allowed := links{}
+
+	for _, link := range values {
+		if authorizer(ctx, rel, link) {
+			allowed = append(allowed, link)
+		}
+	}
+
+	return allowed
+}
+
+// authorize applies the authorizers to every embedded resource, wrapping streams so that they are filtered as they are consumed.
+func (resources resources) authorize(ctx context.Context, links LinkAuthorizer, templates TemplateAuthorizer) resources {
+	result := resources
+
+	result.items = make([]Resource, 0, len(resources.items))
+	for _, item := range resources.items {
+		result.items = append(result.items, item.Authorize(ctx, links, templates))
+	}
+
+	result.streams = make([]func(yield func(Resource) bool), 0, len(resources.streams))
+	for _, stream := range resources.streams {
+		stream := stream
+
+		result.streams = append(result.streams, func(yield func(Resource) bool) {
+			stream(func(item Resource) bool {
+				return yield(item.Authorize(ctx, links, templates))
+			})
+		})
+	}
+
+	return result
+}
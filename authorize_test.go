@@ -0,0 +1,121 @@
+package gohalforms_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/kinbiko/jsonassert"
+	"github.com/sazzer/gohalforms"
+	"github.com/stretchr/testify/assert"
+)
+
+type roleKey struct{}
+
+func allowLinks(ctx context.Context, rel string, link gohalforms.Link) bool {
+	return rel != "admin" || ctx.Value(roleKey{}) == "admin"
+}
+
+func allowTemplates(ctx context.Context, name string, template gohalforms.Template) bool {
+	return template.Method != http.MethodDelete || ctx.Value(roleKey{}) == "admin"
+}
+
+func affordances(href string) gohalforms.Resource {
+	resource := gohalforms.NewResource(map[string]any{"href": href})
+	resource.AddLink("self", gohalforms.Link{Href: href})
+	resource.AddLink("admin", gohalforms.Link{Href: href + "/admin"})
+	resource.AddTemplate("default", gohalforms.Template{Method: http.MethodPut})
+	resource.AddTemplate("delete", gohalforms.Template{Method: http.MethodDelete})
+
+	return resource
+}
+
+func TestAuthorizeRemovesDisallowedAffordances(t *testing.T) {
+	t.Parallel()
+
+	resource := affordances("/users")
+	resource.AddEmbedded("item", affordances("/users/1"))
+
+	var produced int
+	resource.AddEmbeddedSeq("stream", countTo(1, &produced))
+
+	authorized := resource.Authorize(context.Background(), allowLinks, allowTemplates)
+
+	encoded, err := gohalforms.Marshal(authorized)
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"href": "/users",
+		"_links": {
+			"self": {"href": "/users"}
+		},
+		"_templates": {
+			"default": {"method": "PUT", "properties": null}
+		},
+		"_embedded": {
+			"item": {
+				"href": "/users/1",
+				"_links": {
+					"self": {"href": "/users/1"}
+				},
+				"_templates": {
+					"default": {"method": "PUT", "properties": null}
+				}
+			},
+			"stream": [
+				{"index": 1}
+			]
+		}
+	}`)
+
+	// The original resource keeps every affordance.
+	encoded, err = gohalforms.Marshal(resource)
+	assert.NoError(t, err)
+	assert.Contains(t, string(encoded), "/users/admin")
+	assert.Contains(t, string(encoded), "DELETE")
+}
+
+func TestAuthorizeAllowsEverything(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithValue(context.Background(), roleKey{}, "admin")
+	resource := affordances("/users")
+
+	authorized, err := gohalforms.Marshal(resource.Authorize(ctx, allowLinks, allowTemplates))
+	assert.NoError(t, err)
+
+	unfiltered, err := gohalforms.Marshal(resource.Authorize(context.Background(), nil, nil))
+	assert.NoError(t, err)
+
+	original, err := gohalforms.Marshal(resource)
+	assert.NoError(t, err)
+
+	assert.JSONEq(t, string(original), string(authorized))
+	assert.JSONEq(t, string(original), string(unfiltered))
+}
+
+func TestAuthorizeStreamedResources(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(nil)
+	resource.AddEmbeddedSeq("items", func(yield func(gohalforms.Resource) bool) {
+		yield(affordances("/users/1"))
+	})
+
+	encoded, err := gohalforms.Marshal(resource.Authorize(context.Background(), allowLinks, allowTemplates))
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"_embedded": {
+			"items": [
+				{
+					"href": "/users/1",
+					"_links": {"self": {"href": "/users/1"}},
+					"_templates": {"default": {"method": "PUT", "properties": null}}
+				}
+			]
+		}
+	}`)
+}
@@ -0,0 +1,97 @@
+package gohalforms_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sazzer/gohalforms"
+)
+
+type benchmarkBody struct {
+	ID          int64    `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	Score       float64  `json:"score"`
+}
+
+func benchmarkResource() gohalforms.Resource {
+	resource := gohalforms.NewResource(benchmarkBody{ID: 1, Name: "Collection", Description: "A collection of items"})
+	resource.AddLink("self", gohalforms.Link{Href: "/items"})
+
+	for i := int64(0); i < 100; i++ {
+		item := gohalforms.NewResource(benchmarkBody{
+			ID:          i,
+			Name:        "Item",
+			Description: "An item in the collection",
+			Tags:        []string{"a", "b", "c"},
+			Score:       float64(i) / 3,
+		})
+		item.AddLink("self", gohalforms.Link{Href: "/items/1"})
+
+		resource.AddEmbedded("items", item)
+	}
+
+	return resource
+}
+
+// roundTrip marshals a payload and its hypermedia members the way that Resource.MarshalJSON used to, by marshalling the
+// payload, unmarshalling it into a map and then marshalling the map.
+func roundTrip(payload any, links map[string]any, embedded []json.RawMessage) ([]byte, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	intermediate := map[string]any{}
+	if err := json.Unmarshal(raw, &intermediate); err != nil {
+		return nil, err
+	}
+
+	intermediate["_links"] = links
+
+	if embedded != nil {
+		intermediate["_embedded"] = map[string]any{"items": embedded}
+	}
+
+	return json.Marshal(intermediate)
+}
+
+func BenchmarkMarshalRoundTrip(b *testing.B) {
+	links := map[string]any{"self": gohalforms.Link{Href: "/items"}}
+	itemLinks := map[string]any{"self": gohalforms.Link{Href: "/items/1"}}
+	item := benchmarkBody{ID: 1, Name: "Item", Description: "An item in the collection", Tags: []string{"a", "b", "c"}, Score: 1.0 / 3}
+	body := benchmarkBody{ID: 1, Name: "Collection", Description: "A collection of items"}
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		embedded := make([]json.RawMessage, 0, 100)
+
+		for j := 0; j < 100; j++ {
+			encoded, err := roundTrip(item, itemLinks, nil)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			embedded = append(embedded, encoded)
+		}
+
+		if _, err := roundTrip(body, links, embedded); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMarshalSpliced(b *testing.B) {
+	resource := benchmarkResource()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := gohalforms.Marshal(resource); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package gohalforms
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// CollectionJSONContentType is the media type of resources encoded as Collection+JSON.
+const CollectionJSONContentType = "application/vnd.collection+json"
+
+// collectionDocument is a Collection+JSON document.
+type collectionDocument struct {
+	Collection collection `json:"collection"`
+}
+
+// collection is a Collection+JSON collection object.
+type collection struct {
+	Version  string              `json:"version"`
+	Href     string              `json:"href,omitempty"`
+	Links    []collectionLink    `json:"links,omitempty"`
+	Items    []collectionItem    `json:"items,omitempty"`
+	Queries  []collectionQuery   `json:"queries,omitempty"`
+	Template *collectionTemplate `json:"template,omitempty"`
+}
+
+// collectionLink is a Collection+JSON link.
+type collectionLink struct {
+	Rel    string `json:"rel"`
+	Href   string `json:"href"`
+	Prompt string `json:"prompt,omitempty"`
+}
+
+// collectionItem is a single item of a Collection+JSON collection.
+type collectionItem struct {
+	Href  string           `json:"href,omitempty"`
+	Data  []collectionData `json:"data,omitempty"`
+	Links []collectionLink `json:"links,omitempty"`
+}
+
+// collectionData is a single name and value pair, within an item, query or template.
+type collectionData struct {
+	Name   string `json:"name"`
+	Value  any    `json:"value"`
+	Prompt string `json:"prompt,omitempty"`
+}
+
+// collectionQuery is a Collection+JSON query, describing a search that can be made with a GET request.
+type collectionQuery struct {
+	Rel    string           `json:"rel"`
+	Href   string           `json:"href"`
+	Prompt string           `json:"prompt,omitempty"`
+	Data   []collectionData `json:"data,omitempty"`
+}
+
+// collectionTemplate is a Collection+JSON template, describing the data to write when creating or updating an item.
+type collectionTemplate struct {
+	Data []collectionData `json:"data"`
+}
+
+// listsItems determines whether the resource is a collection that can be encoded as Collection+JSON without losing
+// anything important, meaning that it either has embedded resources to become its items or has no payload of its own.
+func (resource Resource) listsItems() bool {
+	return len(resource.embedded.keys(false)) > 0 || resource.payload == nil
+}
+
+// MarshalCollectionJSON encodes a HAL (Hypertext Application Language) resource representing a collection as a
+// Collection+JSON document, so that the same resource can be served to clients that speak Collection+JSON. Every
+// embedded resource becomes one of the "items", whose payload members become its "data" and whose self link becomes
+// its "href". Links become "links", with the self link becoming the "href" of the collection. The default template
+// becomes the "template" unless it is submitted with GET, and every template submitted with GET becomes one of the
+// "queries".
+//
+// Collection+JSON has no place for the payload of the collection itself, templated links, or templates that are
+// neither queries nor the default, so these are left out. For this reason, Negotiate only chooses Collection+JSON for
+// resources that have embedded resources or no payload.
+//
+// Parameters:
+//
+//	resource - The Resource instance to encode.
+//	options - Any options to control the encoding.
+//
+// Returns:
+//
+//	The Collection+JSON representation of the resource, or an error if it could not be encoded.
+//
+// Example:
+//
+//	// Encode a page of users for a Collection+JSON client.
+//	users := gohalforms.NewResource(nil)
+//	users.AddLink("self", gohalforms.Link{Href: "/users"})
+//	users.AddEmbedded("users", user)
+//	users.AddTemplate("default", gohalforms.Template{Method: http.MethodPost, Properties: properties})
+//
+//	encoded, err := gohalforms.MarshalCollectionJSON(users)
+func MarshalCollectionJSON(resource Resource, options ...EncodeOption) ([]byte, error) {
+	resource, settings, err := limitedResource(resource, options)
+	if err != nil {
+		return nil, err
+	}
+
+	result := collection{Version: "1.0"}
+	result.Href, result.Links = collectionLinks(resource.links)
+
+	for _, rel := range resource.embedded.keys(false) {
+		values := resource.embedded.get(rel)
+
+		for index, item := range values.all() {
+			converted, err := collectionItemOf(item, settings, values.location("/_embedded/"+escapePointer(rel), index))
+			if err != nil {
+				return nil, err
+			}
+
+			result.Items = append(result.Items, converted)
+		}
+	}
+
+	for _, name := range resource.templates.keys(false) {
+		template := resource.templates.get(name)
+		if !strings.EqualFold(template.effectiveMethod(), http.MethodGet) {
+			continue
+		}
+
+		result.Queries = append(result.Queries, collectionQuery{
+			Rel:    name,
+			Href:   resource.effectiveTarget(template),
+			Prompt: template.Title,
+			Data:   collectionTemplateData(template),
+		})
+	}
+
+	// A single template is the default whatever it was added under, as it is for HAL-FORMS.
+	if templates := resource.encodedTemplates(); templates.has(DefaultTemplateName) {
+		if template := templates.get(DefaultTemplateName); !strings.EqualFold(template.effectiveMethod(), http.MethodGet) {
+			result.Template = &collectionTemplate{Data: collectionTemplateData(template)}
+		}
+	}
+
+	encoded, err := json.Marshal(collectionDocument{Collection: result})
+	if err != nil {
+		return nil, err
+	}
+
+	return settings.limits.checkSize(encoded)
+}
+
+// collectionItemOf converts an embedded resource found at the given location into a Collection+JSON item.
+func collectionItemOf(resource Resource, settings encodeOptions, location string) (collectionItem, error) {
+	item := collectionItem{}
+	item.Href, item.Links = collectionLinks(resource.links)
+
+	payload, err := encodePayload(resource.payload, settings.wrapPayload, location)
+	if err != nil {
+		return collectionItem{}, err
+	}
+
+	members, err := payload.members()
+	if err != nil {
+		return collectionItem{}, err
+	}
+
+	for _, member := range members {
+		item.Data = append(item.Data, collectionData{Name: member.key, Value: member.value})
+	}
+
+	return item, nil
+}
+
+// collectionLinks converts the links of a resource into Collection+JSON links, returning the self link separately as
+// the href of the collection or item.
+func collectionLinks(values *linkset) (string, []collectionLink) {
+	var (
+		href   string
+		result []collectionLink
+	)
+
+	for _, rel := range values.keys(false) {
+		for _, link := range values.get(rel) {
+			if link.Templated {
+				continue
+			}
+
+			if rel == RelSelf && href == "" {
+				href = link.Href
+
+				continue
+			}
+
+			result = append(result, collectionLink{Rel: rel, Href: link.Href, Prompt: link.Title})
+		}
+	}
+
+	return href, result
+}
+
+// collectionTemplateData converts the properties of a template into Collection+JSON data.
+func collectionTemplateData(template Template) []collectionData {
+	data := make([]collectionData, 0, len(template.Properties))
+
+	for _, property := range template.Properties {
+		data = append(data, collectionData{Name: property.Name, Value: property.Value, Prompt: property.Prompt})
+	}
+
+	return data
+}
@@ -0,0 +1,146 @@
+package gohalforms_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/kinbiko/jsonassert"
+	"github.com/sazzer/gohalforms"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalCollectionJSON(t *testing.T) {
+	t.Parallel()
+
+	user := gohalforms.NewResource(map[string]any{"name": "Graham", "age": 42})
+	user.AddLink("self", gohalforms.Link{Href: "/users/1"})
+	user.AddLink("avatar", gohalforms.Link{Href: "/users/1/avatar", Title: "Avatar"})
+
+	resource := gohalforms.NewResource(map[string]any{"total": 1})
+	resource.AddLink("self", gohalforms.Link{Href: "/users"})
+	resource.AddLink("next", gohalforms.Link{Href: "/users?page=2", Title: "Next page"})
+	resource.AddLink("find", gohalforms.Link{Href: "/users/{id}", Templated: true})
+	resource.AddEmbedded("users", user)
+	resource.AddTemplate("default", gohalforms.Template{
+		Method: http.MethodPost,
+		Properties: []gohalforms.Property{
+			gohalforms.TextProperty("name", gohalforms.Prompt("Name")),
+			gohalforms.NumberProperty("age", gohalforms.DefaultValue("18")),
+		},
+	})
+	resource.AddTemplate("search", gohalforms.Template{
+		Method: http.MethodGet,
+		Title:  "Search",
+		Target: "/users/search",
+		Properties: []gohalforms.Property{
+			gohalforms.TextProperty("q"),
+		},
+	})
+	resource.AddTemplate("delete", gohalforms.Template{Method: http.MethodDelete})
+
+	encoded, err := gohalforms.MarshalCollectionJSON(resource)
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"collection": {
+			"version": "1.0",
+			"href": "/users",
+			"links": [{"rel": "next", "href": "/users?page=2", "prompt": "Next page"}],
+			"items": [
+				{
+					"href": "/users/1",
+					"data": [{"name": "age", "value": 42}, {"name": "name", "value": "Graham"}],
+					"links": [{"rel": "avatar", "href": "/users/1/avatar", "prompt": "Avatar"}]
+				}
+			],
+			"queries": [
+				{"rel": "search", "href": "/users/search", "prompt": "Search", "data": [{"name": "q", "value": ""}]}
+			],
+			"template": {
+				"data": [{"name": "name", "value": "", "prompt": "Name"}, {"name": "age", "value": "18"}]
+			}
+		}
+	}`)
+}
+
+func TestMarshalCollectionJSONSingleTemplate(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(nil)
+	resource.AddLink("self", gohalforms.Link{Href: "/users"})
+	resource.AddTemplate("create", gohalforms.Template{
+		Method:     http.MethodPost,
+		Properties: []gohalforms.Property{gohalforms.TextProperty("name")},
+	})
+
+	encoded, err := gohalforms.MarshalCollectionJSON(resource)
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"collection": {
+			"version": "1.0",
+			"href": "/users",
+			"template": {"data": [{"name": "name", "value": ""}]}
+		}
+	}`)
+}
+
+func TestMarshalCollectionJSONNonObjectItem(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(nil)
+	resource.AddEmbedded("tags", gohalforms.NewResource("urgent"))
+
+	_, err := gohalforms.MarshalCollectionJSON(resource)
+	assert.ErrorIs(t, err, gohalforms.ErrPayloadNotObject)
+
+	encoded, err := gohalforms.MarshalCollectionJSON(resource, gohalforms.WithPayloadWrapping("value"))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"collection": {"version": "1.0", "items": [{"data": [{"name": "value", "value": "urgent"}]}]}}`, string(encoded))
+}
+
+func TestMarshalCollectionJSONSingleQuery(t *testing.T) {
+	t.Parallel()
+
+	for _, method := range []string{http.MethodGet, "get"} {
+		resource := gohalforms.NewResource(nil)
+		resource.AddLink("self", gohalforms.Link{Href: "/users"})
+		resource.AddTemplate("search", gohalforms.Template{
+			Method:     method,
+			Properties: []gohalforms.Property{gohalforms.TextProperty("q")},
+		})
+
+		encoded, err := gohalforms.MarshalCollectionJSON(resource)
+		assert.NoError(t, err)
+
+		ja := jsonassert.New(t)
+		ja.Assertf(string(encoded), `{
+			"collection": {
+				"version": "1.0",
+				"href": "/users",
+				"queries": [{"rel": "search", "href": "/users", "data": [{"name": "q", "value": ""}]}]
+			}
+		}`)
+	}
+}
+
+func TestMarshalCollectionJSONLimits(t *testing.T) {
+	t.Parallel()
+
+	produced := 0
+
+	resource := gohalforms.NewResource(nil)
+	resource.AddEmbeddedSeq("items", countTo(100, &produced))
+
+	_, err := gohalforms.MarshalCollectionJSON(resource, gohalforms.WithLimits(gohalforms.Limits{MaxEmbedded: 2}))
+	assert.Equal(t, gohalforms.LimitError{Limit: "embedded", Max: 2, Location: "/_embedded/items"}, err)
+	assert.Equal(t, 3, produced)
+
+	_, err = gohalforms.MarshalCollectionJSON(nestedResource(2), gohalforms.WithLimits(gohalforms.Limits{MaxDepth: 1}))
+	assert.Equal(t, gohalforms.LimitError{Limit: "depth", Max: 1, Location: "/_embedded/child/_embedded/child"}, err)
+
+	_, err = gohalforms.MarshalCollectionJSON(nestedResource(1), gohalforms.WithLimits(gohalforms.Limits{MaxSize: 10}))
+	assert.Equal(t, gohalforms.LimitError{Limit: "size", Max: 10}, err)
+}
@@ -0,0 +1,167 @@
+package gohalforms
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrReservedKey is returned when a payload contains a member that collides with one of the HAL members of the resource.
+var ErrReservedKey = errors.New("payload contains a reserved key")
+
+// reservedKeys are the members of a HAL resource that are written from the hypermedia rather than the payload.
+var reservedKeys = []string{"_embedded", "_links", "_templates"}
+
+// CollisionPolicy determines what happens when a payload contains a "_links", "_embedded" or "_templates" member
+// and the resource also has hypermedia to write under that name.
+type CollisionPolicy int
+
+const (
+	// CollisionFail fails encoding with a ReservedKeyError. This is the default.
+	CollisionFail CollisionPolicy = iota
+	// CollisionPayloadWins writes the payload member, discarding the hypermedia.
+	CollisionPayloadWins
+	// CollisionHypermediaWins writes the hypermedia, discarding the payload member.
+	CollisionHypermediaWins
+	// CollisionMergeLinks merges the links from a payload "_links" object with those of the resource. Links under the same
+	// relation are combined into an array, with those from the payload first. Collisions on other members fail as for CollisionFail.
+	CollisionMergeLinks
+)
+
+// ReservedKeyError describes a payload member that collides with one of the HAL members of the resource.
+type ReservedKeyError struct {
+	// Key is the name of the colliding member.
+	Key string
+	// Location is a JSON Pointer to the resource whose payload contains the member.
+	Location string
+}
+
+// Error returns a description of the collision.
+func (err ReservedKeyError) Error() string {
+	if err.Location == "" {
+		return fmt.Sprintf("payload contains reserved key %q", err.Key)
+	}
+
+	return fmt.Sprintf("payload at %s contains reserved key %q", err.Location, err.Key)
+}
+
+// Unwrap allows a ReservedKeyError to be matched against ErrReservedKey.
+func (err ReservedKeyError) Unwrap() error {
+	return ErrReservedKey
+}
+
+// WithCollisionPolicy configures how collisions between payload members and the HAL members of a resource are resolved.
+//
+// Parameters:
+//
+//	policy - The policy to apply to collisions.
+//
+// Returns:
+//
+//	An EncodeOption applying the policy.
+//
+// Example:
+//
+//	// Combine links that are already present in the payload with those added to the resource.
+//	encoded, err := gohalforms.Marshal(halResource, gohalforms.WithCollisionPolicy(gohalforms.CollisionMergeLinks))
+func WithCollisionPolicy(policy CollisionPolicy) EncodeOption {
+	return func(options *encodeOptions) {
+		options.collisions = policy
+	}
+}
+
+// splitReserved separates any reserved members from the rest of the payload.
+// The payload is only split into its members when a reserved member might be present.
+func (payload payloadObject) splitReserved() (payloadObject, map[string]json.RawMessage, error) {
+	check := false
+
+	for _, key := range reservedKeys {
+		if payload.mightContain(key) {
+			check = true
+		}
+	}
+
+	if !check {
+		return payload, nil, nil
+	}
+
+	members, err := payload.members()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rest := payloadObject{}
+	reserved := map[string]json.RawMessage{}
+
+	for _, member := range members {
+		if member.key == "_embedded" || member.key == "_links" || member.key == "_templates" {
+			reserved[member.key] = member.value
+		} else {
+			rest = rest.with(member)
+		}
+	}
+
+	return rest, reserved, nil
+}
+
+// mergeLinks combines the links from a payload "_links" member with the links of a resource.
+func mergeLinks(raw json.RawMessage, links *linkset, sorted bool) (json.RawMessage, error) {
+	payload := payloadObject(raw)
+	if len(payload) < 2 || payload[0] != '{' {
+		return nil, errors.New("payload _links member is not an object")
+	}
+
+	members, err := payloadObject(payload[1 : len(payload)-1]).members()
+	if err != nil {
+		return nil, err
+	}
+
+	merged := payloadObject{}
+	seen := map[string]bool{}
+
+	for _, member := range members {
+		seen[member.key] = true
+
+		if existing := links.get(member.key); len(existing) > 0 {
+			values := []json.RawMessage{}
+
+			if member.value[0] == '[' {
+				if err := json.Unmarshal(member.value, &values); err != nil {
+					return nil, err
+				}
+			} else {
+				values = append(values, member.value)
+			}
+
+			for _, link := range existing {
+				encoded, err := json.Marshal(link)
+				if err != nil {
+					return nil, err
+				}
+
+				values = append(values, encoded)
+			}
+
+			if member.value, err = json.Marshal(values); err != nil {
+				return nil, err
+			}
+		}
+
+		merged = merged.with(member)
+	}
+
+	for _, rel := range links.keys(sorted) {
+		if seen[rel] {
+			continue
+		}
+
+		encoded, err := json.Marshal(links.get(rel))
+		if err != nil {
+			return nil, err
+		}
+
+		merged = merged.with(member{key: rel, value: encoded})
+	}
+
+	return json.RawMessage("{" + string(merged) + "}"), nil
+}
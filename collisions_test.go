@@ -0,0 +1,118 @@
+package gohalforms_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/kinbiko/jsonassert"
+	"github.com/sazzer/gohalforms"
+	"github.com/stretchr/testify/assert"
+)
+
+func collidingResource() gohalforms.Resource {
+	resource := gohalforms.NewResource(map[string]any{
+		"_links": map[string]any{
+			"self":    map[string]any{"href": "/payloadSelf"},
+			"payload": map[string]any{"href": "/payload"},
+		},
+		"_templates": "unrelated",
+		"hello":      "World!",
+	})
+	resource.AddLink("self", gohalforms.Link{Href: "/testSelfLink"})
+	resource.AddLink("other", gohalforms.Link{Href: "/other"})
+
+	return resource
+}
+
+func TestMarshalCollisionFails(t *testing.T) {
+	t.Parallel()
+
+	_, err := json.Marshal(collidingResource())
+	assert.ErrorIs(t, err, gohalforms.ErrReservedKey)
+
+	outer := gohalforms.NewResource(nil)
+	outer.AddEmbedded("item", collidingResource())
+
+	_, err = gohalforms.Marshal(outer)
+	assert.Equal(t, gohalforms.ReservedKeyError{Key: "_links", Location: "/_embedded/item"}, err)
+	assert.EqualError(t, err, `payload at /_embedded/item contains reserved key "_links"`)
+}
+
+func TestMarshalCollisionPayloadWins(t *testing.T) {
+	t.Parallel()
+
+	encoded, err := gohalforms.Marshal(collidingResource(), gohalforms.WithCollisionPolicy(gohalforms.CollisionPayloadWins))
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"_links": {
+			"self": {"href": "/payloadSelf"},
+			"payload": {"href": "/payload"}
+		},
+		"_templates": "unrelated",
+		"hello":  "World!"
+	}`)
+}
+
+func TestMarshalCollisionHypermediaWins(t *testing.T) {
+	t.Parallel()
+
+	encoded, err := gohalforms.Marshal(collidingResource(), gohalforms.WithCollisionPolicy(gohalforms.CollisionHypermediaWins))
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"_links": {
+			"self": {"href": "/testSelfLink"},
+			"other": {"href": "/other"}
+		},
+		"_templates": "unrelated",
+		"hello":  "World!"
+	}`)
+}
+
+func TestMarshalCollisionMergeLinks(t *testing.T) {
+	t.Parallel()
+
+	encoded, err := gohalforms.Marshal(collidingResource(), gohalforms.WithCollisionPolicy(gohalforms.CollisionMergeLinks))
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"_links": {
+			"self": [
+				{"href": "/payloadSelf"},
+				{"href": "/testSelfLink"}
+			],
+			"payload": {"href": "/payload"},
+			"other": {"href": "/other"}
+		},
+		"_templates": "unrelated",
+		"hello":  "World!"
+	}`)
+}
+
+func TestMarshalCollisionMergeOtherMembers(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(map[string]any{
+		"_templates": "payload",
+	})
+	resource.AddTemplate("default", gohalforms.Template{})
+
+	_, err := gohalforms.Marshal(resource, gohalforms.WithCollisionPolicy(gohalforms.CollisionMergeLinks))
+	assert.Equal(t, gohalforms.ReservedKeyError{Key: "_templates"}, err)
+}
+
+func TestMarshalCollisionMergeInvalidLinks(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(map[string]any{
+		"_links": "payload",
+	})
+	resource.AddLink("self", gohalforms.Link{Href: "/testSelfLink"})
+
+	_, err := gohalforms.Marshal(resource, gohalforms.WithCollisionPolicy(gohalforms.CollisionMergeLinks))
+	assert.ErrorIs(t, err, gohalforms.ErrReservedKey)
+}
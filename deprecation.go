@@ -0,0 +1,280 @@
+package gohalforms
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Deprecation describes when a link, template or resource was deprecated, when it will stop working, and where to find
+// out more. It is signalled using the Deprecation (RFC 9745) and Sunset (RFC 8594) HTTP headers.
+type Deprecation struct {
+	// Date is when the deprecation took effect. If it is zero, the Deprecation header uses the Unix epoch, meaning that it
+	// is already deprecated, and the "deprecationDate" property of links and templates is left out.
+	Date time.Time
+	// Sunset is when the deprecated link, template or resource is expected to stop working. It is optional.
+	Sunset time.Time
+	// Link is the URL of documentation describing the deprecation, such as a migration guide. It is optional.
+	Link string
+}
+
+// Deprecate returns a copy of the link marked as deprecated, with its "deprecation" property set to the URL of the
+// documentation describing the deprecation, as required by HAL. Links without any documentation are marked using the
+// URL of the link itself. Any date of the deprecation and the sunset are kept in the "deprecationDate" and "sunset"
+// properties, in RFC 3339 format, so that clients can see when the link will stop working. These are not part of HAL,
+// so clients that do not recognise them will ignore them.
+//
+// Parameters:
+//
+//	deprecation - The details of the deprecation.
+//
+// Returns:
+//
+//	The deprecated Link.
+//
+// Example:
+//
+//	// Warn clients that the old search will be removed.
+//	halResource.AddLink("search", gohalforms.Link{Href: "/search"}.Deprecate(gohalforms.Deprecation{
+//	    Sunset: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+//	    Link:   "https://example.com/docs/new-search",
+//	}))
+func (link Link) Deprecate(deprecation Deprecation) Link {
+	link.Deprecation = deprecation.Link
+	if link.Deprecation == "" {
+		link.Deprecation = link.Href
+	}
+
+	link.DeprecationDate, link.Sunset = deprecation.dates()
+
+	return link
+}
+
+// Deprecate returns a copy of the template marked as deprecated, in the same way as for links. Templates without any
+// documentation are marked using their target. The "deprecation", "deprecationDate" and "sunset" properties mirror
+// those of deprecated links, and HAL-FORMS clients that do not recognise them will ignore them.
+//
+// Parameters:
+//
+//	deprecation - The details of the deprecation.
+//
+// Returns:
+//
+//	The deprecated Template.
+func (template Template) Deprecate(deprecation Deprecation) Template {
+	template.Deprecation = deprecation.Link
+	if template.Deprecation == "" {
+		template.Deprecation = template.Target
+	}
+
+	template.DeprecationDate, template.Sunset = deprecation.dates()
+
+	return template
+}
+
+// dates returns the dates of the deprecation and the sunset in RFC 3339 format, leaving either empty if it is zero.
+func (deprecation Deprecation) dates() (string, string) {
+	return formatDate(deprecation.Date), formatDate(deprecation.Sunset)
+}
+
+// formatDate formats a date in RFC 3339 format, or returns an empty string if it is zero.
+func formatDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+
+	return date.UTC().Format(time.RFC3339)
+}
+
+// SetDeprecation marks the HAL (Hypertext Application Language) resource itself as deprecated. When it is sent using
+// Send or Stream, the Deprecation, Sunset and Link headers are added to the response.
+//
+// Parameters:
+//
+//	resource - A pointer to the Resource instance to mark as deprecated.
+//	deprecation - The details of the deprecation.
+//
+// Example:
+//
+//	// The v1 representation of users goes away at the end of the year.
+//	halResource.SetDeprecation(gohalforms.Deprecation{
+//	    Date:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+//	    Sunset: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+//	    Link:   "https://example.com/docs/v2-migration",
+//	})
+func (resource *Resource) SetDeprecation(deprecation Deprecation) {
+	resource.deprecation = &deprecation
+}
+
+// Deprecation returns the deprecation of the HAL (Hypertext Application Language) resource, and true if it is deprecated.
+func (resource Resource) Deprecation() (Deprecation, bool) {
+	if resource.deprecation == nil {
+		return Deprecation{}, false
+	}
+
+	return *resource.deprecation, true
+}
+
+// Headers returns the HTTP headers that signal the deprecation.
+//
+// Returns:
+//
+//	The Deprecation header, and the Sunset and Link headers if they apply.
+func (deprecation Deprecation) Headers() http.Header {
+	headers := http.Header{}
+
+	date := deprecation.Date
+	if date.IsZero() {
+		date = time.Unix(0, 0)
+	}
+
+	headers.Set("Deprecation", "@"+strconv.FormatInt(date.Unix(), 10))
+
+	if !deprecation.Sunset.IsZero() {
+		headers.Set("Sunset", deprecation.Sunset.UTC().Format(http.TimeFormat))
+	}
+
+	if deprecation.Link != "" {
+		headers.Add("Link", "<"+deprecation.Link+`>; rel="`+RelDeprecation+`"; type="text/html"`)
+	}
+
+	return headers
+}
+
+// addDeprecationHeaders adds the deprecation headers of a resource to a response, if the resource is deprecated.
+func addDeprecationHeaders(w http.ResponseWriter, resource Resource) {
+	deprecation, deprecated := resource.Deprecation()
+	if !deprecated {
+		return
+	}
+
+	for name, values := range deprecation.Headers() {
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+}
+
+// ParseDeprecation detects whether an HTTP response signals that the requested resource is deprecated, using the
+// Deprecation, Sunset and Link headers. Both the "@" timestamp form of RFC 9745 and the older "true" and HTTP date
+// forms of the Deprecation header are recognised.
+//
+// Parameters:
+//
+//	response - The HTTP response to inspect.
+//
+// Returns:
+//
+//	The details of the deprecation, and true if the response signals one.
+//
+// Example:
+//
+//	response, err := http.Get("https://example.com/users")
+//	if err != nil {
+//	    // Handle the error.
+//	}
+//
+//	if deprecation, deprecated := gohalforms.ParseDeprecation(response); deprecated {
+//	    log.Printf("users are deprecated, see %s", deprecation.Link)
+//	}
+func ParseDeprecation(response *http.Response) (Deprecation, bool) {
+	value := strings.TrimSpace(response.Header.Get("Deprecation"))
+	if value == "" || strings.EqualFold(value, "false") {
+		return Deprecation{}, false
+	}
+
+	var deprecation Deprecation
+
+	if seconds, err := strconv.ParseInt(strings.TrimPrefix(value, "@"), 10, 64); err == nil && strings.HasPrefix(value, "@") {
+		deprecation.Date = time.Unix(seconds, 0).UTC()
+	} else if date, err := http.ParseTime(value); err == nil {
+		deprecation.Date = date
+	}
+
+	if sunset, err := http.ParseTime(response.Header.Get("Sunset")); err == nil {
+		deprecation.Sunset = sunset
+	}
+
+	for _, header := range response.Header.Values("Link") {
+		if target, ok := linkWithRel(header, RelDeprecation); ok {
+			deprecation.Link = target
+		}
+	}
+
+	return deprecation, true
+}
+
+// linkWithRel finds the target of the first link in a Link header value that has the given relation.
+func linkWithRel(header string, rel string) (string, bool) {
+	for _, entry := range strings.Split(header, ",") {
+		parts := strings.Split(entry, ";")
+
+		target := strings.TrimSpace(parts[0])
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+
+		for _, param := range parts[1:] {
+			name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if !found || !strings.EqualFold(strings.TrimSpace(name), "rel") {
+				continue
+			}
+
+			for _, candidate := range strings.Fields(strings.Trim(strings.TrimSpace(value), `"`)) {
+				if strings.EqualFold(candidate, rel) {
+					return target[1 : len(target)-1], true
+				}
+			}
+		}
+	}
+
+	return "", false
+}
+
+// DeprecationTransport is an http.RoundTripper that detects responses signalling that the requested resource is
+// deprecated, and reports them to a hook, so that clients can log their use of deprecated APIs.
+type DeprecationTransport struct {
+	// Base is the RoundTripper used to make requests. If it is nil, http.DefaultTransport is used.
+	Base http.RoundTripper
+	// OnDeprecated is called for every response that signals a deprecation.
+	OnDeprecated func(request *http.Request, deprecation Deprecation)
+}
+
+// RoundTrip makes the request using the base RoundTripper, reporting the response if it signals a deprecation.
+//
+// Parameters:
+//
+//	request - The HTTP request to make.
+//
+// Returns:
+//
+//	The HTTP response, or an error if the request failed.
+//
+// Example:
+//
+//	// Log every use of a deprecated API.
+//	client := &http.Client{
+//	    Transport: &gohalforms.DeprecationTransport{
+//	        OnDeprecated: func(request *http.Request, deprecation gohalforms.Deprecation) {
+//	            log.Printf("%s is deprecated and will be removed on %s", request.URL, deprecation.Sunset)
+//	        },
+//	    },
+//	}
+func (transport *DeprecationTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	base := transport.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	response, err := base.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if deprecation, deprecated := ParseDeprecation(response); deprecated && transport.OnDeprecated != nil {
+		transport.OnDeprecated(request, deprecation)
+	}
+
+	return response, nil
+}
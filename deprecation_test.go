@@ -0,0 +1,177 @@
+package gohalforms_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kinbiko/jsonassert"
+	"github.com/sazzer/gohalforms"
+	"github.com/stretchr/testify/assert"
+)
+
+func deprecation() gohalforms.Deprecation {
+	return gohalforms.Deprecation{
+		Date:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Sunset: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+		Link:   "https://example.com/docs/migration",
+	}
+}
+
+func TestMarshalDeprecatedLinksAndTemplates(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(nil)
+	resource.AddLink("search", gohalforms.Link{Href: "/search"}.Deprecate(deprecation()))
+	resource.AddLink("legacy", gohalforms.Link{Href: "/legacy"}.Deprecate(gohalforms.Deprecation{}))
+	resource.AddTemplate("default", gohalforms.Template{Method: http.MethodPost, Target: "/users"}.Deprecate(deprecation()))
+
+	encoded, err := gohalforms.Marshal(resource)
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"_links": {
+			"legacy": {"href": "/legacy", "deprecation": "/legacy"},
+			"search": {
+				"href": "/search",
+				"deprecation": "https://example.com/docs/migration",
+				"deprecationDate": "2024-01-01T00:00:00Z",
+				"sunset": "2024-12-31T00:00:00Z"
+			}
+		},
+		"_templates": {
+			"default": {
+				"method": "POST",
+				"target": "/users",
+				"properties": null,
+				"deprecation": "https://example.com/docs/migration",
+				"deprecationDate": "2024-01-01T00:00:00Z",
+				"sunset": "2024-12-31T00:00:00Z"
+			}
+		}
+	}`)
+}
+
+func TestDeprecateKeepsSunset(t *testing.T) {
+	t.Parallel()
+
+	sunset := time.Date(2025, 6, 30, 12, 0, 0, 0, time.FixedZone("BST", 3600))
+
+	link := gohalforms.Link{Href: "/search"}.Deprecate(gohalforms.Deprecation{Sunset: sunset})
+	assert.Equal(t, "/search", link.Deprecation)
+	assert.Equal(t, "", link.DeprecationDate)
+	assert.Equal(t, "2025-06-30T11:00:00Z", link.Sunset)
+
+	parsed, err := time.Parse(time.RFC3339, link.Sunset)
+	assert.NoError(t, err)
+	assert.True(t, sunset.Equal(parsed))
+
+	template := gohalforms.Template{Target: "/users"}.Deprecate(gohalforms.Deprecation{Sunset: sunset})
+	assert.Equal(t, "/users", template.Deprecation)
+	assert.Equal(t, "", template.DeprecationDate)
+	assert.Equal(t, "2025-06-30T11:00:00Z", template.Sunset)
+}
+
+func TestDeprecationHeaders(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, http.Header{
+		"Deprecation": {"@1704067200"},
+		"Sunset":      {"Tue, 31 Dec 2024 00:00:00 GMT"},
+		"Link":        {`<https://example.com/docs/migration>; rel="deprecation"; type="text/html"`},
+	}, deprecation().Headers())
+
+	assert.Equal(t, http.Header{
+		"Deprecation": {"@0"},
+	}, gohalforms.Deprecation{}.Headers())
+}
+
+func TestSendDeprecatedResource(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(nil)
+	resource.SetDeprecation(deprecation())
+
+	for _, send := range []func(http.ResponseWriter, gohalforms.Resource, ...gohalforms.EncodeOption) error{gohalforms.Send, gohalforms.Stream} {
+		rec := httptest.NewRecorder()
+		assert.NoError(t, send(rec, resource))
+
+		response := rec.Result()
+		defer response.Body.Close()
+
+		assert.Equal(t, "@1704067200", response.Header.Get("Deprecation"))
+		assert.Equal(t, "Tue, 31 Dec 2024 00:00:00 GMT", response.Header.Get("Sunset"))
+
+		parsed, deprecated := gohalforms.ParseDeprecation(response)
+		assert.True(t, deprecated)
+		assert.Equal(t, deprecation(), parsed)
+	}
+}
+
+func TestSendUndeprecatedResource(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	assert.NoError(t, gohalforms.Send(rec, gohalforms.NewResource(nil)))
+
+	response := rec.Result()
+	defer response.Body.Close()
+
+	assert.Empty(t, response.Header.Values("Deprecation"))
+
+	_, deprecated := gohalforms.ParseDeprecation(response)
+	assert.False(t, deprecated)
+}
+
+func TestParseDeprecationLegacyForms(t *testing.T) {
+	t.Parallel()
+
+	response := &http.Response{Header: http.Header{
+		"Deprecation": {"true"},
+		"Link":        {`</next>; rel="next", <https://example.com/docs>; rel="deprecation"`},
+	}}
+
+	parsed, deprecated := gohalforms.ParseDeprecation(response)
+	assert.True(t, deprecated)
+	assert.Equal(t, gohalforms.Deprecation{Link: "https://example.com/docs"}, parsed)
+
+	response = &http.Response{Header: http.Header{
+		"Deprecation": {"Mon, 01 Jan 2024 00:00:00 GMT"},
+	}}
+
+	parsed, deprecated = gohalforms.ParseDeprecation(response)
+	assert.True(t, deprecated)
+	assert.Equal(t, gohalforms.Deprecation{Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}, parsed)
+}
+
+func TestDeprecationTransport(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resource := gohalforms.NewResource(nil)
+		if r.URL.Path == "/old" {
+			resource.SetDeprecation(deprecation())
+		}
+
+		_ = gohalforms.Send(w, resource)
+	}))
+	defer server.Close()
+
+	var reported []string
+
+	client := &http.Client{Transport: &gohalforms.DeprecationTransport{
+		OnDeprecated: func(request *http.Request, deprecation gohalforms.Deprecation) {
+			reported = append(reported, request.URL.Path+" "+deprecation.Link)
+		},
+	}}
+
+	for _, path := range []string{"/old", "/new"} {
+		response, err := client.Get(server.URL + path)
+		assert.NoError(t, err)
+		response.Body.Close()
+	}
+
+	assert.Equal(t, []string{"/old https://example.com/docs/migration"}, reported)
+}
@@ -1,22 +1,61 @@
 package gohalforms
 
-import "encoding/json"
-
-// resources is a slice of Resource instances used to represent multiple embedded HAL (Hypertext Application Language) resources.
-type resources []Resource
-
-// resourceset is a map where the keys represent relation names, and the values are slices of links that point to HAL resources.
-type resourceset map[string]resources
-
-// MarshalJSON serializes a resources slice to JSON. If there is only one Resource in the slice, it is serialized individually.
-//
-// Returns:
-//
-//	A JSON representation of the resources slice or a single Resource instance.
-func (resources resources) MarshalJSON() ([]byte, error) {
-	if len(resources) == 1 {
-		return json.Marshal(resources[0])
+import "strconv"
+
+// resources holds the HAL (Hypertext Application Language) resources embedded under a single relation, either as
+// individual values or as streams that are only consumed when the resource is encoded.
+type resources struct {
+	items   []Resource
+	streams []func(yield func(Resource) bool)
+}
+
+// resourceset is an ordered map where the keys represent relation names, and the values are the HAL resources embedded under that relation.
+type resourceset = relset[resources]
+
+// streamed determines whether the embedded resources include any streams, in which case they are always encoded as an array.
+func (resources resources) streamed() bool {
+	return len(resources.streams) > 0
+}
+
+// location returns a JSON Pointer to the embedded resource at the given index, given a pointer to the relation. The
+// index is only included when the resources are encoded as an array.
+func (resources resources) location(base string, index int) string {
+	if len(resources.items) == 1 && !resources.streamed() {
+		return base
+	}
+
+	return base + "/" + strconv.Itoa(index)
+}
+
+// all returns every embedded resource, consuming any streams.
+func (resources resources) all() []Resource {
+	result := append([]Resource{}, resources.items...)
+
+	for _, stream := range resources.streams {
+		stream(func(value Resource) bool {
+			result = append(result, value)
+
+			return true
+		})
+	}
+
+	return result
+}
+
+// replaceItems returns resources holding the given items in place of the original ones, which are still encoded as an
+// array if the originals were streamed.
+func replaceItems(original resources, items []Resource) resources {
+	if !original.streamed() {
+		return resources{items: items}
 	}
 
-	return json.Marshal([]Resource(resources))
+	return resources{streams: []func(yield func(Resource) bool){
+		func(yield func(Resource) bool) {
+			for _, item := range items {
+				if !yield(item) {
+					return
+				}
+			}
+		},
+	}}
 }
@@ -0,0 +1,271 @@
+package gohalforms
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrLimitExceeded is returned when encoding a resource would exceed one of the configured Limits.
+var ErrLimitExceeded = errors.New("encoding limit exceeded")
+
+// Limits constrains the size of the document produced when encoding a resource.
+// A zero value for any limit means that it is not enforced.
+type Limits struct {
+	// MaxDepth is the maximum nesting depth of embedded resources. Resources embedded directly in the root are at depth 1.
+	MaxDepth int
+	// MaxEmbedded is the maximum number of embedded resources under a single rel.
+	MaxEmbedded int
+	// MaxSize is the maximum size of the encoded document, in bytes.
+	MaxSize int
+	// Truncate, if set, is called when MaxEmbedded is exceeded instead of failing. The embedded resources beyond the
+	// limit are dropped, and the returned link is stored under the "next" rel so that clients can retrieve the
+	// remainder. It replaces any existing "next" link, such as one added by AddPageLinks, since the remainder of this
+	// page comes before the next one. If several relations are truncated, the link for the last of them is kept.
+	Truncate func(rel string, kept int) Link
+}
+
+// LimitError describes which of the configured Limits was exceeded and where in the document.
+type LimitError struct {
+	// Limit is the name of the limit that was exceeded.
+	Limit string
+	// Max is the configured value of the limit.
+	Max int
+	// Location is a JSON Pointer to the point in the document where the limit was exceeded.
+	Location string
+}
+
+// Error returns a description of the exceeded limit.
+func (err LimitError) Error() string {
+	if err.Location == "" {
+		return fmt.Sprintf("%s limit of %d exceeded", err.Limit, err.Max)
+	}
+
+	return fmt.Sprintf("%s limit of %d exceeded at %s", err.Limit, err.Max, err.Location)
+}
+
+// Unwrap allows a LimitError to be matched against ErrLimitExceeded.
+func (err LimitError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// bufferEmbedded reads the resources embedded under a single relation found at the given location, applying the
+// embedded limit. Streams are read until the limit is exceeded, so at most one more than the limit is held in memory.
+// It returns true if the resources were truncated.
+func (limits Limits) bufferEmbedded(values resources, location string) ([]Resource, bool, error) {
+	items := append([]Resource{}, values.items...)
+
+	exceeded := func() bool {
+		return limits.MaxEmbedded > 0 && len(items) > limits.MaxEmbedded
+	}
+
+	for _, stream := range values.streams {
+		if exceeded() {
+			break
+		}
+
+		stream(func(value Resource) bool {
+			items = append(items, value)
+
+			return !exceeded()
+		})
+	}
+
+	if !exceeded() {
+		return items, false, nil
+	}
+
+	if limits.Truncate == nil {
+		return nil, false, LimitError{Limit: "embedded", Max: limits.MaxEmbedded, Location: location}
+	}
+
+	return items[:limits.MaxEmbedded], true, nil
+}
+
+// truncateLinks returns a copy of the links of a resource whose embedded resources under a relation were truncated,
+// with a link to the remainder replacing any under the "next" rel.
+func (limits Limits) truncateLinks(values *linkset, rel string) *linkset {
+	values = values.clone()
+	values.set(RelNext, links{limits.Truncate(rel, limits.MaxEmbedded)})
+
+	return values
+}
+
+// apply returns a copy of a resource found at the given depth and location with the depth and embedded limits applied
+// throughout, for encoders that convert the whole resource before writing it. Streams are read ahead as for
+// bufferEmbedded, and any truncation is recorded with a "next" link as it is by Marshal.
+func (limits Limits) apply(resource Resource, depth int, location string) (Resource, error) {
+	if limits.MaxDepth == 0 && limits.MaxEmbedded == 0 {
+		return resource, nil
+	}
+
+	if limits.MaxDepth > 0 && depth > limits.MaxDepth {
+		return Resource{}, LimitError{Limit: "depth", Max: limits.MaxDepth, Location: location}
+	}
+
+	result := resource
+	result.embedded = newRelset[resources]()
+
+	for _, rel := range resource.embedded.keys(false) {
+		values := resource.embedded.get(rel)
+		relLocation := location + "/_embedded/" + escapePointer(rel)
+
+		items, truncated, err := limits.bufferEmbedded(values, relLocation)
+		if err != nil {
+			return Resource{}, err
+		}
+
+		if truncated {
+			result.links = limits.truncateLinks(result.links, rel)
+		}
+
+		// The limited resources share their items, so applying the limits in place updates them too.
+		limited := replaceItems(values, items)
+
+		for index, item := range items {
+			if items[index], err = limits.apply(item, depth+1, limited.location(relLocation, index)); err != nil {
+				return Resource{}, err
+			}
+		}
+
+		result.embedded.set(rel, limited)
+	}
+
+	return result, nil
+}
+
+// checkSize enforces the size limit on a document that has been encoded in full, returning the document if it is within the limit.
+func (limits Limits) checkSize(encoded []byte) ([]byte, error) {
+	if limits.MaxSize > 0 && len(encoded) > limits.MaxSize {
+		return nil, LimitError{Limit: "size", Max: limits.MaxSize}
+	}
+
+	return encoded, nil
+}
+
+// EncodeOption configures how a resource is encoded. Every format applies the limits and payload wrapping in the same
+// way, except that formats other than HAL check the size limit once the whole document has been encoded instead of as
+// it is written. The ordering, collision policy and strict relation options only affect HAL.
+type EncodeOption func(*encodeOptions)
+
+// encodeOptions holds the configuration built up from a set of EncodeOption values.
+type encodeOptions struct {
+	limits      Limits
+	ordered     bool
+	collisions  CollisionPolicy
+	wrapPayload string
+	strictRels  bool
+}
+
+// newEncodeOptions builds the configuration from a set of EncodeOption values.
+func newEncodeOptions(options []EncodeOption) encodeOptions {
+	var settings encodeOptions
+	for _, option := range options {
+		option(&settings)
+	}
+
+	return settings
+}
+
+// limitedResource builds the configuration for an encoder that converts the whole resource before writing it, and
+// applies the depth and embedded limits to the resource. The size limit is checked once the resource has been encoded.
+func limitedResource(resource Resource, options []EncodeOption) (Resource, encodeOptions, error) {
+	settings := newEncodeOptions(options)
+
+	limited, err := settings.limits.apply(resource, 0, "")
+	if err != nil {
+		return Resource{}, encodeOptions{}, err
+	}
+
+	return limited, settings, nil
+}
+
+// WithLimits configures the limits that are enforced when encoding a resource.
+//
+// Parameters:
+//
+//	limits - The limits to enforce.
+//
+// Returns:
+//
+//	An EncodeOption applying the limits.
+//
+// Example:
+//
+//	// Refuse to produce responses that are unreasonably large.
+//	encoded, err := gohalforms.Marshal(halResource, gohalforms.WithLimits(gohalforms.Limits{
+//	    MaxDepth:    3,
+//	    MaxEmbedded: 100,
+//	    MaxSize:     1024 * 1024,
+//	}))
+func WithLimits(limits Limits) EncodeOption {
+	return func(options *encodeOptions) {
+		options.limits = limits
+	}
+}
+
+// WithOrderedOutput configures resources to be encoded in a meaningful order instead of the default. By default, the
+// "_embedded", "_links" and "_templates" members are written first, with the relations within each sorted alphabetically,
+// followed by the payload fields in the order that they are encoded. With ordered output, the "_links" member is written
+// first, followed by the payload fields, and then the "_embedded" and "_templates" members. Relations within each of
+// these are written in the order in which they were first added to the resource. In both modes, payload fields keep the
+// order that they are encoded in, which for structs is their declaration order.
+//
+// When combined with a Limits.Truncate function, streamed embedded resources are read ahead up to the limit so that
+// the "next" link can be written before them.
+//
+// Returns:
+//
+//	An EncodeOption applying the ordering.
+//
+// Example:
+//
+//	// Encode a resource with its links at the top, for easier reading.
+//	encoded, err := gohalforms.Marshal(halResource, gohalforms.WithOrderedOutput())
+func WithOrderedOutput() EncodeOption {
+	return func(options *encodeOptions) {
+		options.ordered = true
+	}
+}
+
+// Marshal encodes a HAL (Hypertext Application Language) resource to JSON using the provided options.
+//
+// Parameters:
+//
+//	resource - The Resource instance to encode.
+//	options - Any options to control the encoding.
+//
+// Returns:
+//
+//	The JSON representation of the resource, or an error if it could not be encoded.
+//
+// Example:
+//
+//	// Encode a HAL resource, failing if it embeds resources too deeply.
+//	encoded, err := gohalforms.Marshal(halResource, gohalforms.WithLimits(gohalforms.Limits{MaxDepth: 2}))
+//	if errors.Is(err, gohalforms.ErrLimitExceeded) {
+//	    // Handle the oversized resource.
+//	}
+func Marshal(resource Resource, options ...EncodeOption) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	state := newEncodeState(&buffer, options)
+	if err := state.resource(resource, 0, ""); err != nil {
+		return nil, err
+	}
+
+	if err := state.writer.Flush(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// pointerEscaper escapes reference tokens for use within a JSON Pointer, as defined by RFC 6901.
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// escapePointer escapes a reference token for use within a JSON Pointer.
+func escapePointer(token string) string {
+	return pointerEscaper.Replace(token)
+}
@@ -0,0 +1,185 @@
+package gohalforms_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kinbiko/jsonassert"
+	"github.com/sazzer/gohalforms"
+	"github.com/stretchr/testify/assert"
+)
+
+func nestedResource(depth int) gohalforms.Resource {
+	resource := gohalforms.NewResource(map[string]any{"depth": depth})
+	if depth > 0 {
+		resource.AddEmbedded("child", nestedResource(depth-1))
+	}
+
+	return resource
+}
+
+func TestMarshalWithinLimits(t *testing.T) {
+	t.Parallel()
+
+	encoded, err := gohalforms.Marshal(nestedResource(2), gohalforms.WithLimits(gohalforms.Limits{
+		MaxDepth:    2,
+		MaxEmbedded: 1,
+		MaxSize:     1024,
+	}))
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"depth": 2,
+		"_embedded": {
+			"child": {
+				"depth": 1,
+				"_embedded": {
+					"child": {"depth": 0}
+				}
+			}
+		}
+	}`)
+}
+
+func TestMarshalDepthLimit(t *testing.T) {
+	t.Parallel()
+
+	_, err := gohalforms.Marshal(nestedResource(3), gohalforms.WithLimits(gohalforms.Limits{MaxDepth: 2}))
+	assert.ErrorIs(t, err, gohalforms.ErrLimitExceeded)
+	assert.Equal(t, gohalforms.LimitError{
+		Limit:    "depth",
+		Max:      2,
+		Location: "/_embedded/child/_embedded/child/_embedded/child",
+	}, err)
+}
+
+func TestMarshalSizeLimit(t *testing.T) {
+	t.Parallel()
+
+	_, err := gohalforms.Marshal(nestedResource(3), gohalforms.WithLimits(gohalforms.Limits{MaxSize: 20}))
+	assert.ErrorIs(t, err, gohalforms.ErrLimitExceeded)
+	assert.EqualError(t, err, "size limit of 20 exceeded")
+}
+
+func TestMarshalEmbeddedLimit(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(nil)
+	for i := 0; i < 3; i++ {
+		resource.AddEmbedded("items/all", gohalforms.NewResource(map[string]any{"index": i}))
+	}
+
+	_, err := gohalforms.Marshal(resource, gohalforms.WithLimits(gohalforms.Limits{MaxEmbedded: 2}))
+	assert.ErrorIs(t, err, gohalforms.ErrLimitExceeded)
+	assert.EqualError(t, err, "embedded limit of 2 exceeded at /_embedded/items~1all")
+}
+
+func TestMarshalEmbeddedTruncated(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(nil)
+	resource.AddLink("self", gohalforms.Link{Href: "/items"})
+
+	for i := 0; i < 3; i++ {
+		resource.AddEmbedded("items", gohalforms.NewResource(map[string]any{"index": i}))
+	}
+
+	encoded, err := gohalforms.Marshal(resource, gohalforms.WithLimits(gohalforms.Limits{
+		MaxEmbedded: 2,
+		Truncate: func(rel string, kept int) gohalforms.Link {
+			return gohalforms.Link{Href: fmt.Sprintf("/%s?offset=%d", rel, kept)}
+		},
+	}))
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"_links": {
+			"self": {"href": "/items"},
+			"next": {"href": "/items?offset=2"}
+		},
+		"_embedded": {
+			"items": [
+				{"index": 0},
+				{"index": 1}
+			]
+		}
+	}`)
+
+	// The original resource is left untouched.
+	encoded, err = gohalforms.Marshal(resource)
+	assert.NoError(t, err)
+
+	ja.Assertf(string(encoded), `{
+		"_links": {
+			"self": {"href": "/items"}
+		},
+		"_embedded": {
+			"items": [
+				{"index": 0},
+				{"index": 1},
+				{"index": 2}
+			]
+		}
+	}`)
+}
+
+func TestMarshalOrdered(t *testing.T) {
+	t.Parallel()
+
+	type body struct {
+		Zebra string `json:"zebra"`
+		Apple string `json:"apple"`
+	}
+
+	resource := gohalforms.NewResource(body{Zebra: "Z", Apple: "A"})
+	resource.AddTemplate("default", gohalforms.Template{Method: "PUT"})
+	resource.AddTemplate("delete", gohalforms.Template{Method: "DELETE"})
+	resource.AddEmbedded("second", gohalforms.NewResource(map[string]any{"index": 2}))
+	resource.AddEmbedded("first", gohalforms.NewResource(map[string]any{"index": 1}))
+	resource.AddLink("self", gohalforms.Link{Href: "/self"})
+	resource.AddLink("collection", gohalforms.Link{Href: "/collection"})
+	resource.AddLink("self", gohalforms.Link{Href: "/other"})
+
+	encoded, err := gohalforms.Marshal(resource, gohalforms.WithOrderedOutput())
+	assert.NoError(t, err)
+	assert.Equal(t, `{`+
+		`"_links":{"self":[{"href":"/self"},{"href":"/other"}],"collection":{"href":"/collection"}},`+
+		`"zebra":"Z","apple":"A",`+
+		`"_embedded":{"second":{"index":2},"first":{"index":1}},`+
+		`"_templates":{"default":{"method":"PUT","properties":null},"delete":{"method":"DELETE","properties":null}}`+
+		`}`, string(encoded))
+
+	encoded, err = gohalforms.Marshal(resource)
+	assert.NoError(t, err)
+	assert.Equal(t, `{`+
+		`"_embedded":{"first":{"index":1},"second":{"index":2}},`+
+		`"_links":{"collection":{"href":"/collection"},"self":[{"href":"/self"},{"href":"/other"}]},`+
+		`"_templates":{"default":{"method":"PUT","properties":null},"delete":{"method":"DELETE","properties":null}},`+
+		`"zebra":"Z","apple":"A"`+
+		`}`, string(encoded))
+}
+
+func TestMarshalOrderedTruncatedStream(t *testing.T) {
+	t.Parallel()
+
+	produced := 0
+
+	resource := gohalforms.NewResource(nil)
+	resource.AddLink("self", gohalforms.Link{Href: "/items"})
+	resource.AddEmbeddedSeq("items", countTo(100, &produced))
+
+	encoded, err := gohalforms.Marshal(resource, gohalforms.WithOrderedOutput(), gohalforms.WithLimits(gohalforms.Limits{
+		MaxEmbedded: 2,
+		Truncate: func(rel string, kept int) gohalforms.Link {
+			return gohalforms.Link{Href: fmt.Sprintf("/%s?offset=%d", rel, kept)}
+		},
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, produced)
+	assert.Equal(t, `{`+
+		`"_links":{"self":{"href":"/items"},"next":{"href":"/items?offset=2"}},`+
+		`"_embedded":{"items":[{"index":1},{"index":2}]}`+
+		`}`, string(encoded))
+}
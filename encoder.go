@@ -0,0 +1,479 @@
+package gohalforms
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Encoder writes HAL (Hypertext Application Language) resources directly to an output stream, without building the
+// whole document in memory first.
+type Encoder struct {
+	w       io.Writer
+	options []EncodeOption
+}
+
+// NewEncoder creates a new Encoder that writes to w.
+//
+// Parameters:
+//
+//	w - The io.Writer that encoded resources are written to.
+//	options - Any options to control the encoding.
+//
+// Returns:
+//
+//	An Encoder writing to w.
+//
+// Example:
+//
+//	// Stream a large collection straight to the response.
+//	halResource := gohalforms.NewResource(nil)
+//	halResource.AddEmbeddedSeq("items", rowsFromDatabase(ctx))
+//
+//	err := gohalforms.NewEncoder(w).Encode(halResource)
+func NewEncoder(w io.Writer, options ...EncodeOption) *Encoder {
+	return &Encoder{
+		w:       w,
+		options: options,
+	}
+}
+
+// Encode writes the JSON representation of a resource to the stream, followed by a newline character.
+// Output is written progressively, so if an error occurs part of the document may already have been written.
+//
+// Parameters:
+//
+//	resource - The Resource instance to encode.
+//
+// Returns:
+//
+//	An error if the resource could not be encoded or written; otherwise, it returns nil.
+func (encoder *Encoder) Encode(resource Resource) error {
+	state := newEncodeState(encoder.w, encoder.options)
+
+	if err := state.resource(resource, 0, ""); err != nil {
+		_ = state.writer.Flush()
+
+		return err
+	}
+
+	if _, err := state.writer.WriteString("\n"); err != nil {
+		return err
+	}
+
+	return state.writer.Flush()
+}
+
+// encodeState tracks the progress of writing a single document.
+type encodeState struct {
+	options encodeOptions
+	writer  *bufio.Writer
+	written int
+	// curies holds the names of the CURIE prefixes in scope for the resource being written, when relations are strict.
+	curies []string
+}
+
+// newEncodeState creates the state for writing a single document to w.
+func newEncodeState(w io.Writer, options []EncodeOption) *encodeState {
+	return &encodeState{
+		options: newEncodeOptions(options),
+		writer:  bufio.NewWriter(w),
+	}
+}
+
+// write writes raw bytes to the output, enforcing the size limit.
+func (state *encodeState) write(data []byte) error {
+	limits := state.options.limits
+
+	if limits.MaxSize > 0 && state.written+len(data) > limits.MaxSize {
+		return LimitError{Limit: "size", Max: limits.MaxSize}
+	}
+
+	state.written += len(data)
+
+	_, err := state.writer.Write(data)
+
+	return err
+}
+
+// writeString writes a raw string to the output, enforcing the size limit.
+func (state *encodeState) writeString(data string) error {
+	return state.write([]byte(data))
+}
+
+// writeJSON marshals a value and writes it to the output.
+func (state *encodeState) writeJSON(value any) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return state.write(encoded)
+}
+
+// writeKey writes an object key, preceded by a separator if it is not the first member of the object.
+func (state *encodeState) writeKey(key string, first *bool) error {
+	if !*first {
+		if err := state.writeString(","); err != nil {
+			return err
+		}
+	}
+
+	*first = false
+
+	if err := state.writeJSON(key); err != nil {
+		return err
+	}
+
+	return state.writeString(":")
+}
+
+// resourceState tracks the progress of writing a single resource within a document.
+type resourceState struct {
+	*encodeState
+	resource Resource
+	depth    int
+	location string
+	payload  payloadObject
+	reserved map[string]json.RawMessage
+	links    *linkset
+	embedded *resourceset
+	first    bool
+}
+
+// resource writes a resource found at the given depth and location within the document.
+func (state *encodeState) resource(resource Resource, depth int, location string) error {
+	limits := state.options.limits
+
+	if limits.MaxDepth > 0 && depth > limits.MaxDepth {
+		return LimitError{Limit: "depth", Max: limits.MaxDepth, Location: location}
+	}
+
+	release, err := state.checkRels(resource, location)
+	if err != nil {
+		return err
+	}
+
+	defer release()
+
+	payload, err := encodePayload(resource.payload, state.options.wrapPayload, location)
+	if err != nil {
+		return err
+	}
+
+	payload, reserved, err := payload.splitReserved()
+	if err != nil {
+		return err
+	}
+
+	current := &resourceState{
+		encodeState: state,
+		resource:    resource,
+		depth:       depth,
+		location:    location,
+		payload:     payload,
+		reserved:    reserved,
+		links:       resource.links,
+		embedded:    resource.embedded,
+		first:       true,
+	}
+
+	members := []func() error{current.writeEmbedded, current.writeLinks, current.writeTemplates, current.writePayload}
+
+	if state.options.ordered {
+		// The links are written before the embedded resources, so any truncation must be known up front.
+		current.embedded = state.truncateAll(current.embedded, current.truncate)
+
+		members = []func() error{current.writeLinks, current.writePayload, current.writeEmbedded, current.writeTemplates}
+	}
+
+	if err := state.writeString("{"); err != nil {
+		return err
+	}
+
+	for _, member := range members {
+		if err := member(); err != nil {
+			return err
+		}
+	}
+
+	return state.writeString("}")
+}
+
+// sorted determines whether relations are written in alphabetical order rather than the order in which they were added.
+func (current *resourceState) sorted() bool {
+	return !current.options.ordered
+}
+
+// truncate records that the resources embedded under a relation were truncated, linking to the remainder.
+func (current *resourceState) truncate(rel string) {
+	current.links = current.options.limits.truncateLinks(current.links, rel)
+}
+
+// hasHypermedia determines whether the resource has any hypermedia to write under a HAL member.
+func (current *resourceState) hasHypermedia(key string) bool {
+	switch key {
+	case "_embedded":
+		return current.embedded.len() > 0
+	case "_links":
+		return current.links.len() > 0
+	case "_templates":
+		return current.resource.templates.len() > 0
+	default:
+		return false
+	}
+}
+
+// writeHypermedia writes a HAL member, resolving any collision with a payload member of the same name.
+func (current *resourceState) writeHypermedia(key string, write func() error) error {
+	value, collides := current.reserved[key]
+	if collides {
+		delete(current.reserved, key)
+	}
+
+	policy := current.options.collisions
+
+	switch {
+	case !collides || policy == CollisionHypermediaWins:
+		if err := current.writeKey(key, &current.first); err != nil {
+			return err
+		}
+
+		return write()
+	case policy == CollisionPayloadWins:
+		if err := current.writeKey(key, &current.first); err != nil {
+			return err
+		}
+
+		return current.write(value)
+	case policy == CollisionMergeLinks && key == "_links":
+		merged, err := mergeLinks(value, current.links, current.sorted())
+		if err != nil {
+			return fmt.Errorf("%w: %v", ReservedKeyError{Key: key, Location: current.location}, err)
+		}
+
+		if err := current.writeKey(key, &current.first); err != nil {
+			return err
+		}
+
+		return current.write(merged)
+	default:
+		return ReservedKeyError{Key: key, Location: current.location}
+	}
+}
+
+// writeEmbedded writes the "_embedded" member of the resource, if it has any embedded resources.
+func (current *resourceState) writeEmbedded() error {
+	if !current.hasHypermedia("_embedded") {
+		return nil
+	}
+
+	return current.writeHypermedia("_embedded", func() error {
+		if err := current.writeString("{"); err != nil {
+			return err
+		}
+
+		first := true
+
+		for _, rel := range current.embedded.keys(current.sorted()) {
+			if err := current.writeKey(rel, &first); err != nil {
+				return err
+			}
+
+			location := current.location + "/_embedded/" + escapePointer(rel)
+
+			truncated, err := current.embeddedRel(current.embedded.get(rel), current.depth+1, location)
+			if err != nil {
+				return err
+			}
+
+			if truncated {
+				current.truncate(rel)
+			}
+		}
+
+		return current.writeString("}")
+	})
+}
+
+// writeLinks writes the "_links" member of the resource, if it has any links.
+func (current *resourceState) writeLinks() error {
+	if !current.hasHypermedia("_links") {
+		return nil
+	}
+
+	return current.writeHypermedia("_links", func() error {
+		return writeRelset(current.encodeState, current.links, current.sorted())
+	})
+}
+
+// writeTemplates writes the "_templates" member of the resource, if it has any templates.
+func (current *resourceState) writeTemplates() error {
+	if !current.hasHypermedia("_templates") {
+		return nil
+	}
+
+	return current.writeHypermedia("_templates", func() error {
+		return writeRelset(current.encodeState, current.resource.encodedTemplates(), current.sorted())
+	})
+}
+
+// writePayload writes the members of the payload.
+func (current *resourceState) writePayload() error {
+	payload := current.payload
+
+	// Reserved payload members without any matching hypermedia are written as part of the payload.
+	for _, key := range reservedKeys {
+		if value, exists := current.reserved[key]; exists && !current.hasHypermedia(key) {
+			payload = payload.with(member{key: key, value: value})
+		}
+	}
+
+	if len(payload) == 0 {
+		return nil
+	}
+
+	if !current.first {
+		if err := current.writeString(","); err != nil {
+			return err
+		}
+	}
+
+	current.first = false
+
+	return current.write(payload)
+}
+
+// writeRelset writes a set of values keyed by relation as a JSON object.
+func writeRelset[V any](state *encodeState, values *relset[V], sorted bool) error {
+	if err := state.writeString("{"); err != nil {
+		return err
+	}
+
+	first := true
+
+	for _, rel := range values.keys(sorted) {
+		if err := state.writeKey(rel, &first); err != nil {
+			return err
+		}
+
+		if err := state.writeJSON(values.get(rel)); err != nil {
+			return err
+		}
+	}
+
+	return state.writeString("}")
+}
+
+// truncateAll applies the embedded limit to every relation ahead of writing them, calling truncate for each relation
+// that exceeds it. Streams are read until the limit is exceeded, so at most one more than the limit is held in memory.
+func (state *encodeState) truncateAll(embedded *resourceset, truncate func(rel string)) *resourceset {
+	limits := state.options.limits
+	if limits.MaxEmbedded == 0 || limits.Truncate == nil {
+		return embedded
+	}
+
+	result := newRelset[resources]()
+
+	for _, rel := range embedded.keys(false) {
+		values := embedded.get(rel)
+
+		// This cannot fail, since the resources beyond the limit are truncated.
+		items, truncated, _ := limits.bufferEmbedded(values, "")
+		if truncated {
+			truncate(rel)
+		}
+
+		result.set(rel, replaceItems(values, items))
+	}
+
+	return result
+}
+
+// embeddedRel writes the resources embedded under a single relation, consuming any streams.
+// It returns true if the resources were truncated because they exceeded the configured limit.
+func (state *encodeState) embeddedRel(values resources, depth int, location string) (bool, error) {
+	limits := state.options.limits
+	items := values.items
+	truncated := false
+
+	if limits.MaxEmbedded > 0 && len(items) > limits.MaxEmbedded {
+		if limits.Truncate == nil {
+			return false, LimitError{Limit: "embedded", Max: limits.MaxEmbedded, Location: location}
+		}
+
+		items = items[:limits.MaxEmbedded]
+		truncated = true
+	}
+
+	if len(items) == 1 && !values.streamed() {
+		return truncated, state.resource(items[0], depth, location)
+	}
+
+	if err := state.writeString("["); err != nil {
+		return false, err
+	}
+
+	count := 0
+
+	// emit writes a single embedded resource, returning false if no more should be written.
+	emit := func(value Resource) (bool, error) {
+		if limits.MaxEmbedded > 0 && count >= limits.MaxEmbedded {
+			if limits.Truncate == nil {
+				return false, LimitError{Limit: "embedded", Max: limits.MaxEmbedded, Location: location}
+			}
+
+			truncated = true
+
+			return false, nil
+		}
+
+		if count > 0 {
+			if err := state.writeString(","); err != nil {
+				return false, err
+			}
+		}
+
+		if err := state.resource(value, depth, location+"/"+strconv.Itoa(count)); err != nil {
+			return false, err
+		}
+
+		count++
+
+		return true, nil
+	}
+
+	for _, item := range items {
+		if _, err := emit(item); err != nil {
+			return false, err
+		}
+	}
+
+	for _, stream := range values.streams {
+		if truncated {
+			break
+		}
+
+		var err error
+
+		stream(func(value Resource) bool {
+			var more bool
+
+			more, err = emit(value)
+
+			return more
+		})
+
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if err := state.writeString("]"); err != nil {
+		return false, err
+	}
+
+	return truncated, nil
+}
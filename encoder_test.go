@@ -0,0 +1,131 @@
+package gohalforms_test
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kinbiko/jsonassert"
+	"github.com/sazzer/gohalforms"
+	"github.com/stretchr/testify/assert"
+)
+
+func countTo(limit int, produced *int) func(yield func(gohalforms.Resource) bool) {
+	return func(yield func(gohalforms.Resource) bool) {
+		for i := 1; i <= limit; i++ {
+			*produced = i
+
+			if !yield(gohalforms.NewResource(map[string]any{"index": i})) {
+				return
+			}
+		}
+	}
+}
+
+func TestEncodeStream(t *testing.T) {
+	t.Parallel()
+
+	produced := 0
+
+	resource := gohalforms.NewResource(map[string]any{"hello": "World!"})
+	resource.AddEmbedded("items", gohalforms.NewResource(map[string]any{"index": 0}))
+	resource.AddEmbeddedSeq("items", countTo(2, &produced))
+	resource.AddEmbeddedSeq("single", countTo(1, &produced))
+
+	var buffer bytes.Buffer
+	err := gohalforms.NewEncoder(&buffer).Encode(resource)
+	assert.NoError(t, err)
+	assert.Equal(t, byte('\n'), buffer.Bytes()[buffer.Len()-1])
+
+	ja := jsonassert.New(t)
+	ja.Assertf(buffer.String(), `{
+		"hello": "World!",
+		"_embedded": {
+			"items": [
+				{"index": 0},
+				{"index": 1},
+				{"index": 2}
+			],
+			"single": [
+				{"index": 1}
+			]
+		}
+	}`)
+}
+
+func TestEncodeStreamTruncated(t *testing.T) {
+	t.Parallel()
+
+	produced := 0
+
+	resource := gohalforms.NewResource(nil)
+	resource.AddEmbeddedSeq("items", countTo(100, &produced))
+
+	encoded, err := gohalforms.Marshal(resource, gohalforms.WithLimits(gohalforms.Limits{
+		MaxEmbedded: 2,
+		Truncate: func(rel string, kept int) gohalforms.Link {
+			return gohalforms.Link{Href: "/items?offset=2"}
+		},
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, produced)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"_links": {
+			"next": {"href": "/items?offset=2"}
+		},
+		"_embedded": {
+			"items": [
+				{"index": 1},
+				{"index": 2}
+			]
+		}
+	}`)
+}
+
+func TestEncodeStreamExceedingLimit(t *testing.T) {
+	t.Parallel()
+
+	produced := 0
+
+	resource := gohalforms.NewResource(nil)
+	resource.AddEmbeddedSeq("items", countTo(100, &produced))
+
+	err := gohalforms.NewEncoder(io.Discard, gohalforms.WithLimits(gohalforms.Limits{MaxEmbedded: 5})).Encode(resource)
+	assert.ErrorIs(t, err, gohalforms.ErrLimitExceeded)
+	assert.EqualError(t, err, "embedded limit of 5 exceeded at /_embedded/items")
+	assert.Equal(t, 6, produced)
+}
+
+func TestStreamResponse(t *testing.T) {
+	t.Parallel()
+
+	produced := 0
+
+	resource := gohalforms.NewResource(nil)
+	resource.AddEmbeddedSeq("items", countTo(2, &produced))
+
+	rec := httptest.NewRecorder()
+	err := gohalforms.Stream(rec, resource)
+	assert.NoError(t, err)
+
+	response := rec.Result()
+	defer response.Body.Close()
+
+	assert.Equal(t, []string{"application/hal+json; charset=utf-8"}, response.Header.Values("content-type"))
+
+	body, err := io.ReadAll(response.Body)
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(body), `{
+		"_embedded": {
+			"items": [
+				{"index": 1},
+				{"index": 2}
+			]
+		}
+	}`)
+}
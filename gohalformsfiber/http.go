@@ -11,6 +11,7 @@ import (
 //
 //	c - The *fiber.Ctx instance representing the Fiber context to which the response will be sent.
 //	resource - The gohalforms.Resource instance representing the HAL resource to be sent as a response.
+//	options - Any options to control how the resource is encoded.
 //
 // Returns:
 //
@@ -28,13 +29,94 @@ import (
 //	if err != nil {
 //	    // Handle the error, e.g., log it or send an alternative response.
 //	}
-func Send(c *fiber.Ctx, resource gohalforms.Resource) error {
-	err := c.JSON(resource)
+func Send(c *fiber.Ctx, resource gohalforms.Resource, options ...gohalforms.EncodeOption) error {
+	encoded, err := gohalforms.Marshal(resource, options...)
 	if err != nil {
 		return err
 	}
 
 	c.Response().Header.Set("Content-Type", resource.GetContentType())
+	addDeprecationHeaders(c, resource)
 
-	return nil
+	return c.Send(encoded)
+}
+
+// SendOptions sends a list of option values as a Fiber response, for use as the handler of the target of a LinkOption.
+//
+// Parameters:
+//
+//	c - The *fiber.Ctx instance representing the Fiber context to which the response will be sent.
+//	option - The LinkOption whose target is serving the values, which determines the prompt and value fields.
+//	values - The option values, such as gohalforms.InlineOptionValue, strings or any struct that encodes to a JSON object.
+//
+// Returns:
+//
+//	An error if there was an issue encoding and sending the response; otherwise, it returns nil.
+//
+// Example:
+//
+//	// Serve the available colours from the link target.
+//	err := gohalformsfiber.SendOptions(c, colours, []gohalforms.InlineOptionValue{
+//	    {Prompt: "Red", Value: "red"},
+//	})
+func SendOptions[T any](c *fiber.Ctx, option gohalforms.LinkOption, values []T) error {
+	encoded, err := gohalforms.MarshalOptions(option, values)
+	if err != nil {
+		return err
+	}
+
+	contentType := option.Link.Type
+	if contentType == "" {
+		contentType = fiber.MIMEApplicationJSONCharsetUTF8
+	}
+
+	c.Response().Header.Set("Content-Type", contentType)
+
+	return c.Send(encoded)
+}
+
+// Respond sends a HAL (Hypertext Application Language) resource as a Fiber response to the client, in the format chosen
+// by content negotiation on the Accept header of the request.
+//
+// Parameters:
+//
+//	c - The *fiber.Ctx instance representing the Fiber context to which the response will be sent.
+//	resource - The gohalforms.Resource instance representing the resource to be sent as a response.
+//	options - Any options to control how the resource is encoded.
+//
+// Returns:
+//
+//	An error if there was an issue sending the response; otherwise, it returns nil.
+//
+// Example:
+//
+//	// Serve both HAL and Siren clients from the same handler.
+//	err := gohalformsfiber.Respond(c, halResource)
+func Respond(c *fiber.Ctx, resource gohalforms.Resource, options ...gohalforms.EncodeOption) error {
+	format := gohalforms.Negotiate(c.Get(fiber.HeaderAccept), resource)
+
+	encoded, err := format.Marshal(resource, options...)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header.Set("Content-Type", format.ContentType(resource))
+	c.Vary(fiber.HeaderAccept)
+	addDeprecationHeaders(c, resource)
+
+	return c.Send(encoded)
+}
+
+// addDeprecationHeaders adds the deprecation headers of a resource to the response, if the resource is deprecated.
+func addDeprecationHeaders(c *fiber.Ctx, resource gohalforms.Resource) {
+	deprecation, deprecated := resource.Deprecation()
+	if !deprecated {
+		return
+	}
+
+	for name, values := range deprecation.Headers() {
+		for _, value := range values {
+			c.Response().Header.Add(name, value)
+		}
+	}
 }
@@ -192,3 +192,75 @@ func TestSendResourceWithTemplate(t *testing.T) {
 		"hello":  "World!"
 	}`)
 }
+
+func TestSendOptions(t *testing.T) {
+	t.Parallel()
+
+	app := fiber.New()
+	app.Get("/colours", func(c *fiber.Ctx) error {
+		return gohalformsfiber.SendOptions(c, gohalforms.LinkOption{}, []gohalforms.InlineOptionValue{
+			{Prompt: "Red", Value: "red"},
+		})
+	})
+
+	response, err := app.Test(httptest.NewRequest(http.MethodGet, "/colours", nil))
+	assert.NoError(t, err)
+
+	defer response.Body.Close()
+
+	assert.Equal(t, []string{"application/json; charset=utf-8"}, response.Header.Values("content-type"))
+
+	body, err := io.ReadAll(response.Body)
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(body), `[{"prompt": "Red", "value": "red"}]`)
+}
+
+func TestSendDeprecatedResource(t *testing.T) {
+	t.Parallel()
+
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		resource := gohalforms.NewResource(nil)
+		resource.SetDeprecation(gohalforms.Deprecation{Link: "https://example.com/docs"})
+
+		return gohalformsfiber.Send(c, resource)
+	})
+
+	response, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.NoError(t, err)
+
+	defer response.Body.Close()
+
+	assert.Equal(t, "@0", response.Header.Get("Deprecation"))
+	assert.Equal(t, `<https://example.com/docs>; rel="deprecation"; type="text/html"`, response.Header.Get("Link"))
+}
+
+func TestRespondSiren(t *testing.T) {
+	t.Parallel()
+
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		resource := gohalforms.NewResource(map[string]any{"hello": "World!"})
+
+		return gohalformsfiber.Respond(c, resource)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Accept", "application/vnd.siren+json")
+
+	response, err := app.Test(request)
+	assert.NoError(t, err)
+
+	defer response.Body.Close()
+
+	assert.Equal(t, "application/vnd.siren+json", response.Header.Get("content-type"))
+	assert.Equal(t, "Accept", response.Header.Get("vary"))
+
+	body, err := io.ReadAll(response.Body)
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(body), `{"properties": {"hello": "World!"}}`)
+}
@@ -0,0 +1,305 @@
+package gohalforms
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// HALXMLContentType is the media type of resources encoded as HAL-XML.
+const HALXMLContentType = "application/hal+xml"
+
+// ErrInvalidXMLName is returned when a payload member cannot be encoded as XML because its name is not a valid element name.
+var ErrInvalidXMLName = errors.New("payload member is not a valid XML name")
+
+// XMLNameError describes a payload member whose name is not a valid XML element name.
+type XMLNameError struct {
+	// Name is the name of the payload member.
+	Name string
+	// Location is a JSON Pointer to the resource whose payload contains the member.
+	Location string
+}
+
+// Error returns a description of the invalid name.
+func (err XMLNameError) Error() string {
+	if err.Location == "" {
+		return fmt.Sprintf("payload member %q is not a valid XML name", err.Name)
+	}
+
+	return fmt.Sprintf("payload member %q at %s is not a valid XML name", err.Name, err.Location)
+}
+
+// Unwrap allows an XMLNameError to be matched against ErrInvalidXMLName.
+func (err XMLNameError) Unwrap() error {
+	return ErrInvalidXMLName
+}
+
+// ErrNestedXMLArray is returned when a payload member cannot be encoded as XML because it holds an array directly within
+// an array, which has no unambiguous representation as repeated elements.
+var ErrNestedXMLArray = errors.New("payload member contains nested arrays")
+
+// XMLArrayError describes a payload member holding an array directly within an array.
+type XMLArrayError struct {
+	// Name is the name of the payload member.
+	Name string
+	// Location is a JSON Pointer to the resource whose payload contains the member.
+	Location string
+}
+
+// Error returns a description of the nested arrays.
+func (err XMLArrayError) Error() string {
+	if err.Location == "" {
+		return fmt.Sprintf("payload member %q contains nested arrays", err.Name)
+	}
+
+	return fmt.Sprintf("payload member %q at %s contains nested arrays", err.Name, err.Location)
+}
+
+// Unwrap allows an XMLArrayError to be matched against ErrNestedXMLArray.
+func (err XMLArrayError) Unwrap() error {
+	return ErrNestedXMLArray
+}
+
+// xmlReservedKeys are the payload members that would be mistaken for the links and embedded resources of a HAL-XML resource.
+var xmlReservedKeys = map[string]bool{"link": true, "resource": true}
+
+// xmlLink is a HAL-XML link element.
+type xmlLink struct {
+	XMLName         xml.Name `xml:"link"`
+	Rel             string   `xml:"rel,attr"`
+	Href            string   `xml:"href,attr"`
+	Templated       bool     `xml:"templated,attr,omitempty"`
+	Type            string   `xml:"type,attr,omitempty"`
+	Deprecation     string   `xml:"deprecation,attr,omitempty"`
+	Name            string   `xml:"name,attr,omitempty"`
+	Profile         string   `xml:"profile,attr,omitempty"`
+	Title           string   `xml:"title,attr,omitempty"`
+	HrefLang        string   `xml:"hreflang,attr,omitempty"`
+	DeprecationDate string   `xml:"deprecationDate,attr,omitempty"`
+	Sunset          string   `xml:"sunset,attr,omitempty"`
+}
+
+// MarshalHALXML encodes a HAL (Hypertext Application Language) resource as HAL-XML, following the hal+xml draft, so
+// that the same resource can be served to clients that can only consume XML. The resource becomes a "resource" element
+// whose "href" is its self link. Its other links become "link" elements, each payload member becomes a child element,
+// and embedded resources become nested "resource" elements with a "rel" attribute.
+//
+// Nested objects in the payload become nested elements, and arrays become an element for each value, with an empty
+// array becoming a single empty element. Arrays directly within arrays cannot be told apart once repeated, so they fail
+// with an XMLArrayError. Payload members named "link" or "resource" would be mistaken for hypermedia, so they fail with
+// a ReservedKeyError, and members whose names are not valid XML names fail with an XMLNameError. HAL-XML has no
+// equivalent of templates, so these are left out.
+//
+// Parameters:
+//
+//	resource - The Resource instance to encode.
+//	options - Any options to control the encoding.
+//
+// Returns:
+//
+//	The HAL-XML representation of the resource, or an error if it could not be encoded.
+//
+// Example:
+//
+//	// Encode a HAL resource for a client that only consumes XML.
+//	encoded, err := gohalforms.MarshalHALXML(halResource)
+func MarshalHALXML(resource Resource, options ...EncodeOption) ([]byte, error) {
+	resource, settings, err := limitedResource(resource, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+
+	encoder := xml.NewEncoder(&buffer)
+
+	if err := writeXMLResource(encoder, resource, "", settings, ""); err != nil {
+		return nil, err
+	}
+
+	if err := encoder.Flush(); err != nil {
+		return nil, err
+	}
+
+	return settings.limits.checkSize(buffer.Bytes())
+}
+
+// writeXMLResource writes a resource found at the given location as a HAL-XML resource element, embedded under the given relation.
+func writeXMLResource(encoder *xml.Encoder, resource Resource, rel string, settings encodeOptions, location string) error {
+	start := xml.StartElement{Name: xml.Name{Local: "resource"}}
+
+	var (
+		links []xmlLink
+		self  bool
+	)
+
+	for _, linkRel := range resource.links.keys(false) {
+		for _, link := range resource.links.get(linkRel) {
+			if linkRel == RelSelf && !self {
+				self = true
+				start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "href"}, Value: link.Href})
+
+				continue
+			}
+
+			links = append(links, xmlLink{
+				Rel:             linkRel,
+				Href:            link.Href,
+				Templated:       link.Templated,
+				Type:            link.Type,
+				Deprecation:     link.Deprecation,
+				Name:            link.Name,
+				Profile:         link.Profile,
+				Title:           link.Title,
+				HrefLang:        link.HrefLang,
+				DeprecationDate: link.DeprecationDate,
+				Sunset:          link.Sunset,
+			})
+		}
+	}
+
+	if rel != "" {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "rel"}, Value: rel})
+	}
+
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+
+	for _, link := range links {
+		if err := encoder.Encode(link); err != nil {
+			return err
+		}
+	}
+
+	if err := writeXMLPayload(encoder, resource, settings, location); err != nil {
+		return err
+	}
+
+	for _, embeddedRel := range resource.embedded.keys(false) {
+		values := resource.embedded.get(embeddedRel)
+
+		for index, item := range values.all() {
+			itemLocation := values.location(location+"/_embedded/"+escapePointer(embeddedRel), index)
+
+			if err := writeXMLResource(encoder, item, embeddedRel, settings, itemLocation); err != nil {
+				return err
+			}
+		}
+	}
+
+	return encoder.EncodeToken(start.End())
+}
+
+// writeXMLPayload writes each member of the payload of a resource as a child element.
+func writeXMLPayload(encoder *xml.Encoder, resource Resource, settings encodeOptions, location string) error {
+	payload, err := encodePayload(resource.payload, settings.wrapPayload, location)
+	if err != nil {
+		return err
+	}
+
+	members, err := payload.members()
+	if err != nil {
+		return err
+	}
+
+	for _, member := range members {
+		if xmlReservedKeys[member.key] {
+			return ReservedKeyError{Key: member.key, Location: location}
+		}
+
+		if err := writeXMLValue(encoder, member.key, member.value, location); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeXMLValue writes a JSON value as an element with the given name. Objects become nested elements, arrays become
+// an element for each value, and null and empty arrays become an empty element.
+func writeXMLValue(encoder *xml.Encoder, name string, value json.RawMessage, location string) error {
+	if !validXMLName(name) {
+		return XMLNameError{Name: name, Location: location}
+	}
+
+	switch value[0] {
+	case '[':
+		var values []json.RawMessage
+		if err := json.Unmarshal(value, &values); err != nil {
+			return err
+		}
+
+		if len(values) == 0 {
+			return encoder.EncodeElement("", xml.StartElement{Name: xml.Name{Local: name}})
+		}
+
+		for _, item := range values {
+			if item[0] == '[' {
+				return XMLArrayError{Name: name, Location: location}
+			}
+
+			if err := writeXMLValue(encoder, name, item, location); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case '{':
+		start := xml.StartElement{Name: xml.Name{Local: name}}
+		if err := encoder.EncodeToken(start); err != nil {
+			return err
+		}
+
+		members, err := payloadObject(value[1 : len(value)-1]).members()
+		if err != nil {
+			return err
+		}
+
+		for _, member := range members {
+			if err := writeXMLValue(encoder, member.key, member.value, location); err != nil {
+				return err
+			}
+		}
+
+		return encoder.EncodeToken(start.End())
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber()
+
+	var scalar any
+	if err := decoder.Decode(&scalar); err != nil {
+		return err
+	}
+
+	text := ""
+	if scalar != nil {
+		text = fmt.Sprint(scalar)
+	}
+
+	return encoder.EncodeElement(text, xml.StartElement{Name: xml.Name{Local: name}})
+}
+
+// validXMLName determines whether a name can be used as an XML element name. Names containing colons are rejected,
+// since they would be treated as namespace prefixes, as are names reserved by XML itself.
+func validXMLName(name string) bool {
+	if name == "" || strings.HasPrefix(strings.ToLower(name), "xml") {
+		return false
+	}
+
+	for index, char := range name {
+		switch {
+		case unicode.IsLetter(char) || char == '_':
+		case index > 0 && (unicode.IsDigit(char) || char == '-' || char == '.'):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
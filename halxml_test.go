@@ -0,0 +1,140 @@
+package gohalforms_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sazzer/gohalforms"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalHALXML(t *testing.T) {
+	t.Parallel()
+
+	order := gohalforms.NewResource(map[string]any{"total": 30.5, "currency": "GBP", "status": "shipped"})
+	order.AddLink("self", gohalforms.Link{Href: "/orders/123"})
+	order.AddLink("customer", gohalforms.Link{Href: "/customers/bob", Title: "Bob & Co"})
+
+	resource := gohalforms.NewResource(map[string]any{
+		"currentlyProcessing": 14,
+		"shippedToday":        20,
+		"tags":                []string{"a", "b"},
+		"address":             map[string]any{"city": "London", "postcode": nil},
+	})
+	resource.AddLink("self", gohalforms.Link{Href: "/orders"})
+	resource.AddLink("next", gohalforms.Link{Href: "/orders?page=2"})
+	resource.AddLink("find", gohalforms.Link{Href: "/orders{?id}", Templated: true})
+	resource.AddEmbedded("order", order)
+	resource.AddTemplate("default", gohalforms.Template{Method: http.MethodPost})
+
+	encoded, err := gohalforms.MarshalHALXML(resource)
+	assert.NoError(t, err)
+	assert.Equal(t, `<resource href="/orders">`+
+		`<link rel="next" href="/orders?page=2"></link>`+
+		`<link rel="find" href="/orders{?id}" templated="true"></link>`+
+		`<address><city>London</city><postcode></postcode></address>`+
+		`<currentlyProcessing>14</currentlyProcessing>`+
+		`<shippedToday>20</shippedToday>`+
+		`<tags>a</tags><tags>b</tags>`+
+		`<resource href="/orders/123" rel="order">`+
+		`<link rel="customer" href="/customers/bob" title="Bob &amp; Co"></link>`+
+		`<currency>GBP</currency><status>shipped</status><total>30.5</total>`+
+		`</resource>`+
+		`</resource>`, string(encoded))
+}
+
+func TestMarshalHALXMLErrors(t *testing.T) {
+	t.Parallel()
+
+	embedded := gohalforms.NewResource(map[string]any{"link": "/elsewhere"})
+	resource := gohalforms.NewResource(nil)
+	resource.AddEmbedded("item", embedded)
+
+	_, err := gohalforms.MarshalHALXML(resource)
+	assert.ErrorIs(t, err, gohalforms.ErrReservedKey)
+	assert.EqualError(t, err, `payload at /_embedded/item contains reserved key "link"`)
+
+	_, err = gohalforms.MarshalHALXML(gohalforms.NewResource(map[string]any{"first name": "Graham"}))
+
+	var nameError gohalforms.XMLNameError
+	assert.ErrorAs(t, err, &nameError)
+	assert.ErrorIs(t, err, gohalforms.ErrInvalidXMLName)
+	assert.EqualError(t, err, `payload member "first name" is not a valid XML name`)
+
+	_, err = gohalforms.MarshalHALXML(gohalforms.NewResource(map[string]any{"ids": []any{map[string]any{"2nd": 1}}}))
+	assert.ErrorIs(t, err, gohalforms.ErrInvalidXMLName)
+}
+
+func TestRespondHALXML(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(map[string]any{"hello": "World!"})
+	resource.AddLink("self", gohalforms.Link{Href: "/"})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "application/hal+xml")
+
+	rec := httptest.NewRecorder()
+	assert.NoError(t, gohalforms.Respond(rec, r, resource))
+
+	response := rec.Result()
+	defer response.Body.Close()
+
+	assert.Equal(t, "application/hal+xml", response.Header.Get("content-type"))
+
+	body, err := io.ReadAll(response.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "<resource href=\"/\"><hello>World!</hello></resource>\n", string(body))
+}
+
+func TestMarshalHALXMLArrays(t *testing.T) {
+	t.Parallel()
+
+	encoded, err := gohalforms.MarshalHALXML(gohalforms.NewResource(map[string]any{"tags": []string{}, "name": "Graham"}))
+	assert.NoError(t, err)
+	assert.Equal(t, `<resource><name>Graham</name><tags></tags></resource>`, string(encoded))
+
+	embedded := gohalforms.NewResource(map[string]any{"matrix": [][]int{{1, 2}, {3}}})
+	resource := gohalforms.NewResource(nil)
+	resource.AddEmbedded("item", embedded)
+	resource.AddEmbedded("item", embedded)
+
+	_, err = gohalforms.MarshalHALXML(resource)
+
+	var arrayError gohalforms.XMLArrayError
+	assert.ErrorAs(t, err, &arrayError)
+	assert.ErrorIs(t, err, gohalforms.ErrNestedXMLArray)
+	assert.EqualError(t, err, `payload member "matrix" at /_embedded/item/0 contains nested arrays`)
+
+	_, err = gohalforms.MarshalHALXML(gohalforms.NewResource(map[string]any{"matrix": [][]int{{}}}))
+	assert.ErrorIs(t, err, gohalforms.ErrNestedXMLArray)
+}
+
+func TestMarshalHALXMLLimits(t *testing.T) {
+	t.Parallel()
+
+	_, err := gohalforms.MarshalHALXML(nestedResource(2), gohalforms.WithLimits(gohalforms.Limits{MaxDepth: 1}))
+	assert.Equal(t, gohalforms.LimitError{Limit: "depth", Max: 1, Location: "/_embedded/child/_embedded/child"}, err)
+
+	produced := 0
+
+	resource := gohalforms.NewResource(nil)
+	resource.AddEmbeddedSeq("items", countTo(100, &produced))
+
+	encoded, err := gohalforms.MarshalHALXML(resource, gohalforms.WithLimits(gohalforms.Limits{
+		MaxEmbedded: 1,
+		Truncate: func(rel string, kept int) gohalforms.Link {
+			return gohalforms.Link{Href: "/items?offset=1"}
+		},
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, produced)
+	assert.Equal(t, `<resource><link rel="next" href="/items?offset=1"></link>`+
+		`<resource rel="items"><index>1</index></resource></resource>`, string(encoded))
+
+	encoded, err = gohalforms.MarshalHALXML(nestedResource(1), gohalforms.WithLimits(gohalforms.Limits{MaxSize: 10}))
+	assert.Equal(t, gohalforms.LimitError{Limit: "size", Max: 10}, err)
+	assert.Nil(t, encoded)
+}
@@ -1,9 +1,6 @@
 package gohalforms
 
-import (
-	"encoding/json"
-	"net/http"
-)
+import "net/http"
 
 // GetContentType returns the appropriate content type for the HAL (Hypertext Application Language) resource based on its content.
 //
@@ -21,9 +18,9 @@ import (
 //	// Get the content type for the HAL resource.
 //	contentType := halResource.GetContentType()
 func (resource Resource) GetContentType() string {
-	if len(resource.templates) > 0 {
+	if resource.templates.len() > 0 {
 		return "application/prs.hal-forms+json; charset=utf-8"
-	} else if len(resource.links) > 0 || len(resource.embedded) > 0 {
+	} else if resource.links.len() > 0 || resource.embedded.len() > 0 {
 		return "application/hal+json; charset=utf-8"
 	} else {
 		return "application/json; charset=utf-8"
@@ -36,6 +33,7 @@ func (resource Resource) GetContentType() string {
 //
 //	w - The http.ResponseWriter where the response will be written.
 //	resource - The Resource instance representing the HAL resource to be sent as a response.
+//	options - Any options to control how the resource is encoded.
 //
 // Returns:
 //
@@ -53,8 +51,49 @@ func (resource Resource) GetContentType() string {
 //	if err != nil {
 //	    // Handle the error, e.g., log it or send an alternative response.
 //	}
-func Send(w http.ResponseWriter, resource Resource) error {
+func Send(w http.ResponseWriter, resource Resource, options ...EncodeOption) error {
+	encoded, err := Marshal(resource, options...)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Add("content-type", resource.GetContentType())
+	addDeprecationHeaders(w, resource)
+
+	_, err = w.Write(append(encoded, '\n'))
+
+	return err
+}
+
+// Stream sends a HAL (Hypertext Application Language) resource as an HTTP response to the client, writing it progressively
+// instead of building the whole response in memory first. This is intended for resources with large embedded collections
+// added using AddEmbeddedSeq. Unlike Send, the response headers are written before encoding starts,
+// so an error part way through results in a truncated response body.
+//
+// Parameters:
+//
+//	w - The http.ResponseWriter where the response will be written.
+//	resource - The Resource instance representing the HAL resource to be sent as a response.
+//	options - Any options to control how the resource is encoded.
+//
+// Returns:
+//
+//	An error if there was an issue encoding and sending the response; otherwise, it returns nil.
+//
+// Example:
+//
+//	// Create a HAL resource that streams every row from the database.
+//	halResource := gohalforms.NewResource(nil)
+//	halResource.AddEmbeddedSeq("items", rowsFromDatabase(ctx))
+//
+//	// Stream the HAL resource as an HTTP response.
+//	err := gohalforms.Stream(w, halResource)
+//	if err != nil {
+//	    // Log the error; the response has already been started.
+//	}
+func Stream(w http.ResponseWriter, resource Resource, options ...EncodeOption) error {
 	w.Header().Add("content-type", resource.GetContentType())
+	addDeprecationHeaders(w, resource)
 
-	return json.NewEncoder(w).Encode(resource)
+	return NewEncoder(w, options...).Encode(resource)
 }
@@ -175,3 +175,24 @@ func TestSendResourceWithTemplate(t *testing.T) {
 		"hello":  "World!"
 	}`)
 }
+
+func TestSendExceedingLimits(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(map[string]any{
+		"hello": "World!",
+	})
+
+	rec := httptest.NewRecorder()
+	err := gohalforms.Send(rec, resource, gohalforms.WithLimits(gohalforms.Limits{MaxSize: 5}))
+	assert.ErrorIs(t, err, gohalforms.ErrLimitExceeded)
+
+	response := rec.Result()
+	defer response.Body.Close()
+
+	assert.Empty(t, response.Header.Values("content-type"))
+
+	body, err := io.ReadAll(response.Body)
+	assert.NoError(t, err)
+	assert.Empty(t, body)
+}
@@ -0,0 +1,280 @@
+package gohalforms
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// InputType is the type of input control that a client should use for a template property.
+type InputType string
+
+// The input types defined by HAL-FORMS, which follow the HTML input types.
+const (
+	InputCheckbox      InputType = "checkbox"
+	InputColor         InputType = "color"
+	InputDate          InputType = "date"
+	InputDateTimeLocal InputType = "datetime-local"
+	InputEmail         InputType = "email"
+	InputFile          InputType = "file"
+	InputHidden        InputType = "hidden"
+	InputMonth         InputType = "month"
+	InputNumber        InputType = "number"
+	InputPassword      InputType = "password"
+	InputRadio         InputType = "radio"
+	InputRange         InputType = "range"
+	InputSearch        InputType = "search"
+	InputTel           InputType = "tel"
+	InputText          InputType = "text"
+	InputTextArea      InputType = "textarea"
+	InputTime          InputType = "time"
+	InputURL           InputType = "url"
+	InputWeek          InputType = "week"
+)
+
+// knownInputTypes are the input types that are recognised.
+var knownInputTypes = map[InputType]bool{
+	InputCheckbox:      true,
+	InputColor:         true,
+	InputDate:          true,
+	InputDateTimeLocal: true,
+	InputEmail:         true,
+	InputFile:          true,
+	InputHidden:        true,
+	InputMonth:         true,
+	InputNumber:        true,
+	InputPassword:      true,
+	InputRadio:         true,
+	InputRange:         true,
+	InputSearch:        true,
+	InputTel:           true,
+	InputText:          true,
+	InputTextArea:      true,
+	InputTime:          true,
+	InputURL:           true,
+	InputWeek:          true,
+}
+
+// Known determines whether the input type is one of the recognised input types.
+func (inputType InputType) Known() bool {
+	return knownInputTypes[inputType]
+}
+
+// Numeric determines whether the input type accepts numbers, and so can be constrained by numeric bounds.
+func (inputType InputType) Numeric() bool {
+	return inputType == InputNumber || inputType == InputRange
+}
+
+// Temporal determines whether the input type accepts dates or times, and so can be constrained by date or time bounds.
+func (inputType InputType) Temporal() bool {
+	return inputType == InputDate || inputType == InputDateTimeLocal || inputType == InputTime
+}
+
+// layout returns the time layout used for values of a temporal input type.
+func (inputType InputType) layout() string {
+	switch inputType {
+	case InputDate:
+		return "2006-01-02"
+	case InputDateTimeLocal:
+		return "2006-01-02T15:04"
+	case InputTime:
+		return "15:04"
+	default:
+		return ""
+	}
+}
+
+// ErrInvalidBound is returned when a property bound cannot be decoded.
+var ErrInvalidBound = errors.New("invalid bound")
+
+// Bound is a minimum or maximum constraint on the value of a template property. Numeric bounds are encoded as JSON
+// numbers, and date and time bounds are encoded as strings in the format used by the matching input type.
+type Bound struct {
+	number    float64
+	timestamp time.Time
+	inputType InputType
+}
+
+// NumberBound creates a bound for a number or range property. The value may be negative or fractional.
+//
+// Parameters:
+//
+//	value - The limit of the property value.
+//
+// Returns:
+//
+//	A pointer to the Bound, suitable for use as Property.Min or Property.Max.
+//
+// Example:
+//
+//	// Allow temperatures between -40.5 and 50.
+//	property := gohalforms.Property{
+//	    Name: "temperature",
+//	    Type: gohalforms.InputNumber,
+//	    Min:  gohalforms.NumberBound(-40.5),
+//	    Max:  gohalforms.NumberBound(50),
+//	    Step: 0.5,
+//	}
+func NumberBound(value float64) *Bound {
+	return &Bound{number: value, inputType: InputNumber}
+}
+
+// DateBound creates a bound for a date property. Only the date portion of the value is used.
+//
+// Parameters:
+//
+//	value - The limit of the property value.
+//
+// Returns:
+//
+//	A pointer to the Bound, suitable for use as Property.Min or Property.Max.
+func DateBound(value time.Time) *Bound {
+	return &Bound{timestamp: value, inputType: InputDate}
+}
+
+// DateTimeBound creates a bound for a datetime-local property. The value is used to the nearest minute, ignoring the time zone.
+//
+// Parameters:
+//
+//	value - The limit of the property value.
+//
+// Returns:
+//
+//	A pointer to the Bound, suitable for use as Property.Min or Property.Max.
+func DateTimeBound(value time.Time) *Bound {
+	return &Bound{timestamp: value, inputType: InputDateTimeLocal}
+}
+
+// TimeBound creates a bound for a time property. Only the time portion of the value is used, to the nearest minute.
+//
+// Parameters:
+//
+//	value - The limit of the property value.
+//
+// Returns:
+//
+//	A pointer to the Bound, suitable for use as Property.Min or Property.Max.
+func TimeBound(value time.Time) *Bound {
+	return &Bound{timestamp: value, inputType: InputTime}
+}
+
+// Number returns the value of a numeric bound, and true if the bound is numeric.
+func (bound Bound) Number() (float64, bool) {
+	return bound.number, bound.inputType.Numeric()
+}
+
+// Time returns the value of a date or time bound, and true if the bound is a date or time.
+// The value is truncated to the precision of the input type.
+func (bound Bound) Time() (time.Time, bool) {
+	if !bound.inputType.Temporal() {
+		return time.Time{}, false
+	}
+
+	layout := bound.inputType.layout()
+	value, _ := time.Parse(layout, bound.timestamp.Format(layout))
+
+	return value, true
+}
+
+// Suits determines whether the bound can be used to constrain a property of the given input type.
+func (bound Bound) Suits(inputType InputType) bool {
+	if bound.inputType.Numeric() {
+		return inputType.Numeric()
+	}
+
+	return bound.inputType == inputType
+}
+
+// Compare compares the bound with another of the same kind, returning -1, 0 or +1 if it is less than, equal to or
+// greater than the other. Bounds of different kinds cannot be compared, and return false.
+func (bound Bound) Compare(other Bound) (int, bool) {
+	if bound.inputType.Numeric() && other.inputType.Numeric() {
+		switch {
+		case bound.number < other.number:
+			return -1, true
+		case bound.number > other.number:
+			return 1, true
+		default:
+			return 0, true
+		}
+	}
+
+	if bound.inputType != other.inputType {
+		return 0, false
+	}
+
+	left, _ := bound.Time()
+	right, _ := other.Time()
+
+	switch {
+	case left.Before(right):
+		return -1, true
+	case left.After(right):
+		return 1, true
+	default:
+		return 0, true
+	}
+}
+
+// numberValue returns the value of an optional numeric bound, and true if it is set and numeric.
+func (bound *Bound) numberValue() (float64, bool) {
+	if bound == nil {
+		return 0, false
+	}
+
+	return bound.Number()
+}
+
+// timeValue returns the value of an optional date or time bound, and true if it is set and a date or time.
+func (bound *Bound) timeValue() (time.Time, bool) {
+	if bound == nil {
+		return time.Time{}, false
+	}
+
+	return bound.Time()
+}
+
+// String returns the bound as it appears in the encoded property.
+func (bound Bound) String() string {
+	if bound.inputType.Numeric() {
+		return strconv.FormatFloat(bound.number, 'f', -1, 64)
+	}
+
+	return bound.timestamp.Format(bound.inputType.layout())
+}
+
+// MarshalJSON serializes the bound to JSON, as a number for numeric bounds and as a string for date and time bounds.
+func (bound Bound) MarshalJSON() ([]byte, error) {
+	if bound.inputType.Numeric() {
+		return []byte(bound.String()), nil
+	}
+
+	return json.Marshal(bound.String())
+}
+
+// UnmarshalJSON deserializes a bound from JSON, accepting either a number or a date or time string.
+func (bound *Bound) UnmarshalJSON(data []byte) error {
+	var number float64
+	if err := json.Unmarshal(data, &number); err == nil {
+		*bound = *NumberBound(number)
+
+		return nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidBound, data)
+	}
+
+	for _, inputType := range []InputType{InputDate, InputDateTimeLocal, InputTime} {
+		if timestamp, err := time.Parse(inputType.layout(), value); err == nil {
+			*bound = Bound{timestamp: timestamp, inputType: inputType}
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s", ErrInvalidBound, data)
+}
@@ -0,0 +1,137 @@
+package gohalforms_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kinbiko/jsonassert"
+	"github.com/sazzer/gohalforms"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalBounds(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(nil)
+	resource.AddTemplate("default", gohalforms.Template{
+		Properties: []gohalforms.Property{
+			{
+				Name: "temperature",
+				Type: gohalforms.InputNumber,
+				Min:  gohalforms.NumberBound(-40.5),
+				Max:  gohalforms.NumberBound(50),
+				Step: 0.5,
+			},
+			{
+				Name: "birthday",
+				Type: gohalforms.InputDate,
+				Min:  gohalforms.DateBound(time.Date(1900, 1, 1, 12, 0, 0, 0, time.UTC)),
+			},
+			{
+				Name: "meeting",
+				Type: gohalforms.InputDateTimeLocal,
+				Max:  gohalforms.DateTimeBound(time.Date(2030, 6, 30, 17, 30, 15, 0, time.UTC)),
+			},
+			{
+				Name: "alarm",
+				Type: gohalforms.InputTime,
+				Min:  gohalforms.TimeBound(time.Date(0, 1, 1, 6, 0, 0, 0, time.UTC)),
+			},
+		},
+	})
+
+	encoded, err := json.Marshal(resource)
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"_templates" : {
+			"default" : {
+				"properties" : [
+					{"name": "temperature", "type": "number", "min": -40.5, "max": 50, "step": 0.5},
+					{"name": "birthday", "type": "date", "min": "1900-01-01"},
+					{"name": "meeting", "type": "datetime-local", "max": "2030-06-30T17:30"},
+					{"name": "alarm", "type": "time", "min": "06:00"}
+				]
+			}
+		}
+	}`)
+}
+
+func TestUnmarshalBounds(t *testing.T) {
+	t.Parallel()
+
+	var property gohalforms.Property
+
+	err := json.Unmarshal([]byte(`{"name": "temperature", "type": "number", "min": -1.5, "max": "2030-06-30"}`), &property)
+	assert.NoError(t, err)
+	assert.Equal(t, gohalforms.InputNumber, property.Type)
+
+	number, ok := property.Min.Number()
+	assert.True(t, ok)
+	assert.Equal(t, -1.5, number)
+
+	date, ok := property.Max.Time()
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2030, 6, 30, 0, 0, 0, 0, time.UTC), date)
+	assert.True(t, property.Max.Suits(gohalforms.InputDate))
+
+	err = json.Unmarshal([]byte(`{"name": "temperature", "min": "yesterday"}`), &property)
+	assert.ErrorIs(t, err, gohalforms.ErrInvalidBound)
+}
+
+func TestValidateBounds(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(nil)
+	resource.AddTemplate("default", gohalforms.Template{
+		Properties: []gohalforms.Property{
+			{Name: "a", Type: gohalforms.InputText, Min: gohalforms.NumberBound(1)},
+			{Name: "b", Type: gohalforms.InputDate, Min: gohalforms.NumberBound(1), Max: gohalforms.DateBound(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))},
+			{
+				Name: "c",
+				Type: gohalforms.InputDate,
+				Min:  gohalforms.DateBound(time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)),
+				Max:  gohalforms.DateBound(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+			},
+			{Name: "d", Type: gohalforms.InputRange, Step: -1},
+		},
+	})
+
+	assert.Equal(t, []gohalforms.Issue{
+		{Location: "/_templates/default/properties/0/min", Message: `min of 1 cannot be used with a property of type "text"`},
+		{Location: "/_templates/default/properties/1/min", Message: `min of 1 cannot be used with a property of type "date"`},
+		{Location: "/_templates/default/properties/1/min", Message: "min of 1 and max of 2020-01-01 are different kinds of bound"},
+		{Location: "/_templates/default/properties/2/min", Message: "min of 2020-01-02 is greater than max of 2020-01-01"},
+		{Location: "/_templates/default/properties/3/step", Message: "step of -1 is negative"},
+	}, resource.Validate())
+}
+
+func TestParseQueryBounds(t *testing.T) {
+	t.Parallel()
+
+	template := gohalforms.NewSearchTemplate("/events", "Search events",
+		gohalforms.Property{Name: "temperature", Type: gohalforms.InputNumber, Min: gohalforms.NumberBound(-10.5)},
+		gohalforms.Property{
+			Name: "after",
+			Type: gohalforms.InputDateTimeLocal,
+			Max:  gohalforms.DateTimeBound(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)),
+		},
+	)
+
+	values, err := template.ParseQuery(httptest.NewRequest(http.MethodGet, "/events?temperature=-10.5&after=2029-12-31T23:59", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, gohalforms.SearchValues{
+		"temperature": -10.5,
+		"after":       time.Date(2029, 12, 31, 23, 59, 0, 0, time.UTC),
+	}, values)
+
+	_, err = template.ParseQuery(httptest.NewRequest(http.MethodGet, "/events?temperature=-11&after=2030-01-01T00:01", nil))
+	assert.Equal(t, gohalforms.FieldErrors{
+		{Name: "temperature", Message: "must be at least -10.5"},
+		{Name: "after", Message: "must not be after 2030-01-01T00:00"},
+	}, err)
+}
@@ -70,6 +70,7 @@ func TestMarshalIntegerBody(t *testing.T) {
 
 	_, err := json.Marshal(resource)
 	assert.Error(t, err)
+	assert.ErrorIs(t, err, gohalforms.ErrPayloadNotObject)
 }
 
 func TestMarshalSelfLink(t *testing.T) {
@@ -353,3 +354,24 @@ func TestMarshalInlineOptions(t *testing.T) {
 		}
 	}`)
 }
+
+func TestMarshalPreservesPayload(t *testing.T) {
+	t.Parallel()
+
+	type body struct {
+		Zebra  string `json:"zebra"`
+		Apple  string `json:"apple"`
+		Number int64  `json:"number"`
+	}
+
+	resource := gohalforms.NewResource(body{
+		Zebra:  "Z",
+		Apple:  "A",
+		Number: 9007199254740993,
+	})
+	resource.AddLink("self", gohalforms.Link{Href: "/testSelfLink"})
+
+	encoded, err := json.Marshal(resource)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"_links":{"self":{"href":"/testSelfLink"}},"zebra":"Z","apple":"A","number":9007199254740993}`, string(encoded))
+}
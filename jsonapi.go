@@ -0,0 +1,260 @@
+package gohalforms
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// JSONAPIContentType is the media type of resources encoded as JSON:API.
+const JSONAPIContentType = "application/vnd.api+json"
+
+// ErrMissingIdentity is returned when a resource is encoded in a format that requires every resource to have a type
+// and id, but one does not.
+var ErrMissingIdentity = errors.New("resource has no identity")
+
+// IdentityError describes a resource that has no type and id, in a format that requires them.
+type IdentityError struct {
+	// Location is a JSON Pointer to the resource within the HAL representation.
+	Location string
+}
+
+// Error returns a description of the resource without an identity.
+func (err IdentityError) Error() string {
+	if err.Location == "" {
+		return "resource has no type and id"
+	}
+
+	return fmt.Sprintf("resource at %s has no type and id", err.Location)
+}
+
+// Unwrap allows an IdentityError to be matched against ErrMissingIdentity.
+func (err IdentityError) Unwrap() error {
+	return ErrMissingIdentity
+}
+
+// identity is the type and id of a resource.
+type identity struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// SetIdentity sets the type and id of the HAL (Hypertext Application Language) resource, which are required when it
+// is encoded in formats that identify resources, such as JSON:API. They are not part of the HAL representation.
+//
+// Parameters:
+//
+//	resource - A pointer to the Resource instance to identify.
+//	resourceType - The type of the resource, such as "articles".
+//	id - The id of the resource, unique within its type.
+//
+// Example:
+//
+//	halResource := gohalforms.NewResource(article)
+//	halResource.SetIdentity("articles", strconv.Itoa(article.ID))
+func (resource *Resource) SetIdentity(resourceType string, id string) {
+	resource.identity = &identity{Type: resourceType, ID: id}
+}
+
+// identified determines whether the resource and every resource embedded within it have an identity, so that it can be
+// encoded as JSON:API. Streamed embedded resources cannot be checked without consuming them, so they are assumed to.
+func (resource Resource) identified() bool {
+	if resource.identity == nil {
+		return false
+	}
+
+	for _, rel := range resource.embedded.keys(false) {
+		for _, item := range resource.embedded.get(rel).items {
+			if !item.identified() {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// jsonapiDocument is a JSON:API top-level document with a single primary resource.
+type jsonapiDocument struct {
+	Data     jsonapiResource   `json:"data"`
+	Included []jsonapiResource `json:"included,omitempty"`
+}
+
+// jsonapiResource is a JSON:API resource object.
+type jsonapiResource struct {
+	identity
+	Attributes    json.RawMessage                `json:"attributes,omitempty"`
+	Relationships map[string]jsonapiRelationship `json:"relationships,omitempty"`
+	Links         map[string]any                 `json:"links,omitempty"`
+}
+
+// jsonapiRelationship is a JSON:API relationship, whose data is either a single resource identifier or an array of them.
+type jsonapiRelationship struct {
+	Data any `json:"data"`
+}
+
+// jsonapiLink is a JSON:API link object, used for links that have more than just an href.
+type jsonapiLink struct {
+	Href     string `json:"href"`
+	Title    string `json:"title,omitempty"`
+	Type     string `json:"type,omitempty"`
+	HrefLang string `json:"hreflang,omitempty"`
+}
+
+// jsonapiReserved are the members that JSON:API does not allow within attributes.
+var jsonapiReserved = map[string]bool{"id": true, "type": true, "links": true, "relationships": true}
+
+// MarshalJSONAPI encodes a HAL (Hypertext Application Language) resource as a JSON:API document, so that the same
+// resource can be served to clients that speak JSON:API. The type and id come from SetIdentity, and the payload
+// becomes the "attributes", leaving out any "id", "type", "links" and "relationships" members that JSON:API forbids
+// there. Links become "links", and each embedded relation becomes a relationship whose resources are added to
+// "included", along with any resources embedded within them.
+//
+// Every resource must have an identity. JSON:API allows only one link per relation, so only the first is kept, and it
+// has no equivalent of templated links or templates, so these are left out.
+//
+// Parameters:
+//
+//	resource - The Resource instance to encode.
+//	options - Any options to control the encoding.
+//
+// Returns:
+//
+//	The JSON:API representation of the resource, or an IdentityError if any resource has no identity.
+//
+// Example:
+//
+//	// Encode an article along with its author.
+//	author := gohalforms.NewResource(map[string]any{"name": "Graham"})
+//	author.SetIdentity("people", "9")
+//
+//	article := gohalforms.NewResource(map[string]any{"title": "Hello"})
+//	article.SetIdentity("articles", "1")
+//	article.AddEmbedded("author", author)
+//
+//	encoded, err := gohalforms.MarshalJSONAPI(article)
+func MarshalJSONAPI(resource Resource, options ...EncodeOption) ([]byte, error) {
+	resource, settings, err := limitedResource(resource, options)
+	if err != nil {
+		return nil, err
+	}
+
+	encoder := jsonapiEncoder{settings: settings, seen: map[identity]bool{}}
+
+	data, err := encoder.resource(resource, "")
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := json.Marshal(jsonapiDocument{Data: data, Included: encoder.included})
+	if err != nil {
+		return nil, err
+	}
+
+	return settings.limits.checkSize(encoded)
+}
+
+// jsonapiEncoder collects the included resources while converting a resource into a JSON:API document.
+type jsonapiEncoder struct {
+	settings encodeOptions
+	included []jsonapiResource
+	seen     map[identity]bool
+}
+
+// resource converts a resource found at the given location into a JSON:API resource object, adding any embedded
+// resources to those that are included.
+func (encoder *jsonapiEncoder) resource(resource Resource, location string) (jsonapiResource, error) {
+	if resource.identity == nil {
+		return jsonapiResource{}, IdentityError{Location: location}
+	}
+
+	result := jsonapiResource{identity: *resource.identity}
+
+	attributes, err := encoder.attributes(resource, location)
+	if err != nil {
+		return jsonapiResource{}, err
+	}
+
+	result.Attributes = attributes
+
+	for _, rel := range resource.links.keys(false) {
+		link := resource.links.get(rel)[0]
+		if link.Templated {
+			continue
+		}
+
+		if result.Links == nil {
+			result.Links = map[string]any{}
+		}
+
+		if link.Title == "" && link.Type == "" && link.HrefLang == "" {
+			result.Links[rel] = link.Href
+		} else {
+			result.Links[rel] = jsonapiLink{Href: link.Href, Title: link.Title, Type: link.Type, HrefLang: link.HrefLang}
+		}
+	}
+
+	for _, rel := range resource.embedded.keys(false) {
+		values := resource.embedded.get(rel)
+		linkage := []identity{}
+
+		for index, item := range values.all() {
+			included, err := encoder.resource(item, values.location(location+"/_embedded/"+escapePointer(rel), index))
+			if err != nil {
+				return jsonapiResource{}, err
+			}
+
+			linkage = append(linkage, included.identity)
+			encoder.include(included)
+		}
+
+		if result.Relationships == nil {
+			result.Relationships = map[string]jsonapiRelationship{}
+		}
+
+		if len(linkage) == 1 && !values.streamed() {
+			result.Relationships[rel] = jsonapiRelationship{Data: linkage[0]}
+		} else {
+			result.Relationships[rel] = jsonapiRelationship{Data: linkage}
+		}
+	}
+
+	return result, nil
+}
+
+// attributes converts the payload of a resource into JSON:API attributes, leaving out the members that JSON:API forbids.
+func (encoder *jsonapiEncoder) attributes(resource Resource, location string) (json.RawMessage, error) {
+	payload, err := encodePayload(resource.payload, encoder.settings.wrapPayload, location)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := payload.members()
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := payloadObject{}
+
+	for _, member := range members {
+		if !jsonapiReserved[member.key] {
+			attributes = attributes.with(member)
+		}
+	}
+
+	if len(attributes) == 0 {
+		return nil, nil
+	}
+
+	return append(append([]byte{'{'}, attributes...), '}'), nil
+}
+
+// include adds a resource to those that are included, unless a resource with the same identity already has been.
+func (encoder *jsonapiEncoder) include(resource jsonapiResource) {
+	if encoder.seen[resource.identity] {
+		return
+	}
+
+	encoder.seen[resource.identity] = true
+	encoder.included = append(encoder.included, resource)
+}
@@ -0,0 +1,155 @@
+package gohalforms_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/kinbiko/jsonassert"
+	"github.com/sazzer/gohalforms"
+	"github.com/stretchr/testify/assert"
+)
+
+func person(id string, name string) gohalforms.Resource {
+	resource := gohalforms.NewResource(map[string]any{"id": id, "name": name})
+	resource.SetIdentity("people", id)
+	resource.AddLink("self", gohalforms.Link{Href: "/people/" + id})
+
+	return resource
+}
+
+func TestMarshalJSONAPI(t *testing.T) {
+	t.Parallel()
+
+	comment := gohalforms.NewResource(map[string]any{"body": "First!"})
+	comment.SetIdentity("comments", "5")
+	comment.AddEmbedded("author", person("2", "Alex"))
+
+	article := gohalforms.NewResource(map[string]any{"id": 1, "type": "article", "title": "Hello"})
+	article.SetIdentity("articles", "1")
+	article.AddLink("self", gohalforms.Link{Href: "/articles/1"})
+	article.AddLink("alternate", gohalforms.Link{Href: "/articles/1.html", Type: "text/html"})
+	article.AddLink("search", gohalforms.Link{Href: "/articles{?q}", Templated: true})
+	article.AddEmbedded("author", person("9", "Graham"))
+	article.AddEmbedded("comments", comment)
+	article.AddEmbedded("comments", gohalforms.NewResource(nil))
+	article.AddTemplate("default", gohalforms.Template{Method: http.MethodPut})
+
+	_, err := gohalforms.MarshalJSONAPI(article)
+	assert.ErrorIs(t, err, gohalforms.ErrMissingIdentity)
+	assert.EqualError(t, err, "resource at /_embedded/comments/1 has no type and id")
+
+	identified := gohalforms.NewResource(map[string]any{"id": 1, "type": "article", "title": "Hello"})
+	identified.SetIdentity("articles", "1")
+	identified.AddLink("self", gohalforms.Link{Href: "/articles/1"})
+	identified.AddLink("alternate", gohalforms.Link{Href: "/articles/1.html", Type: "text/html"})
+	identified.AddLink("search", gohalforms.Link{Href: "/articles{?q}", Templated: true})
+	identified.AddEmbedded("author", person("9", "Graham"))
+	identified.AddEmbedded("comments", comment)
+	identified.AddEmbeddedSeq("editors", func(yield func(gohalforms.Resource) bool) {
+		yield(person("9", "Graham"))
+	})
+
+	encoded, err := gohalforms.MarshalJSONAPI(identified)
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"data": {
+			"type": "articles",
+			"id": "1",
+			"attributes": {"title": "Hello"},
+			"links": {
+				"self": "/articles/1",
+				"alternate": {"href": "/articles/1.html", "type": "text/html"}
+			},
+			"relationships": {
+				"author": {"data": {"type": "people", "id": "9"}},
+				"comments": {"data": {"type": "comments", "id": "5"}},
+				"editors": {"data": [{"type": "people", "id": "9"}]}
+			}
+		},
+		"included": [
+			{
+				"type": "people",
+				"id": "9",
+				"attributes": {"name": "Graham"},
+				"links": {"self": "/people/9"}
+			},
+			{
+				"type": "people",
+				"id": "2",
+				"attributes": {"name": "Alex"},
+				"links": {"self": "/people/2"}
+			},
+			{
+				"type": "comments",
+				"id": "5",
+				"attributes": {"body": "First!"},
+				"relationships": {
+					"author": {"data": {"type": "people", "id": "2"}}
+				}
+			}
+		]
+	}`)
+}
+
+func TestMarshalJSONAPIRootIdentity(t *testing.T) {
+	t.Parallel()
+
+	_, err := gohalforms.MarshalJSONAPI(gohalforms.NewResource(nil))
+
+	var identityError gohalforms.IdentityError
+	assert.ErrorAs(t, err, &identityError)
+	assert.EqualError(t, err, "resource has no type and id")
+}
+
+func TestNegotiateJSONAPI(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(map[string]any{"title": "Hello"})
+	resource.SetIdentity("articles", "1")
+
+	format := gohalforms.Negotiate("application/vnd.api+json", resource)
+	assert.Equal(t, "application/vnd.api+json", format.ContentType(resource))
+
+	encoded, err := format.Marshal(resource)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"data": {"type": "articles", "id": "1", "attributes": {"title": "Hello"}}}`, string(encoded))
+}
+
+func TestMarshalJSONAPILimits(t *testing.T) {
+	t.Parallel()
+
+	resource := person("1", "Graham")
+	resource.AddEmbedded("friends", person("2", "Alex"))
+	resource.AddEmbedded("friends", person("3", "Sam"))
+	resource.AddEmbedded("friends", person("4", "Kim"))
+
+	_, err := gohalforms.MarshalJSONAPI(resource, gohalforms.WithLimits(gohalforms.Limits{MaxEmbedded: 2}))
+	assert.Equal(t, gohalforms.LimitError{Limit: "embedded", Max: 2, Location: "/_embedded/friends"}, err)
+
+	_, err = gohalforms.MarshalJSONAPI(resource, gohalforms.WithLimits(gohalforms.Limits{MaxSize: 10}))
+	assert.Equal(t, gohalforms.LimitError{Limit: "size", Max: 10}, err)
+
+	encoded, err := gohalforms.MarshalJSONAPI(resource, gohalforms.WithLimits(gohalforms.Limits{
+		MaxEmbedded: 1,
+		Truncate: func(rel string, kept int) gohalforms.Link {
+			return gohalforms.Link{Href: "/people/1/friends?offset=1"}
+		},
+	}))
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"data": {
+			"type": "people",
+			"id": "1",
+			"attributes": {"name": "Graham"},
+			"links": {"self": "/people/1", "next": "/people/1/friends?offset=1"},
+			"relationships": {"friends": {"data": {"type": "people", "id": "2"}}}
+		},
+		"included": [
+			{"type": "people", "id": "2", "attributes": {"name": "Alex"}, "links": {"self": "/people/2"}}
+		]
+	}`)
+}
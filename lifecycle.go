@@ -0,0 +1,187 @@
+package gohalforms
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrTransitionNotAllowed is returned when a request does not correspond to a transition allowed from the current state.
+var ErrTransitionNotAllowed = errors.New("transition not allowed")
+
+// Transition describes a single move from one state of a resource lifecycle to another, and the hypermedia that
+// advertises it. The href of the link and the target of the template may be URI templates, which are expanded from
+// the variables provided when the lifecycle is applied.
+type Transition[S comparable] struct {
+	// Rel is the relation under which the link and template are added to the resource.
+	Rel string
+	// To is the state that the resource is in after the transition.
+	To S
+	// Link is the link advertising the transition.
+	Link Link
+	// Template describes how to perform the transition. If it is nil, only the link is added, and the transition is
+	// performed by following the link with GET.
+	Template *Template
+}
+
+// Lifecycle is a state machine describing the transitions that are allowed from each state of a resource, such as an
+// order that moves from draft to submitted to approved. It is used both to advertise the allowed transitions on a
+// resource and to check that incoming requests only perform allowed transitions.
+type Lifecycle[S comparable] struct {
+	transitions map[S][]Transition[S]
+}
+
+// NewLifecycle creates a new Lifecycle with no allowed transitions.
+//
+// Returns:
+//
+//	A pointer to the new Lifecycle.
+//
+// Example:
+//
+//	// Describe the lifecycle of an order.
+//	orders := gohalforms.NewLifecycle[string]().
+//	    Allow("draft", gohalforms.Transition[string]{
+//	        Rel:      "submit",
+//	        To:       "submitted",
+//	        Link:     gohalforms.Link{Href: "/orders/{id}/submit"},
+//	        Template: &gohalforms.Template{Method: http.MethodPost},
+//	    }).
+//	    Allow("submitted", gohalforms.Transition[string]{
+//	        Rel:      "approve",
+//	        To:       "approved",
+//	        Link:     gohalforms.Link{Href: "/orders/{id}/approve"},
+//	        Template: &gohalforms.Template{Method: http.MethodPost},
+//	    })
+func NewLifecycle[S comparable]() *Lifecycle[S] {
+	return &Lifecycle[S]{
+		transitions: map[S][]Transition[S]{},
+	}
+}
+
+// Allow declares transitions that are allowed from a state.
+//
+// Parameters:
+//
+//	lifecycle - A pointer to the Lifecycle to which the transitions should be added.
+//	from - The state in which the transitions are allowed.
+//	transitions - The transitions that are allowed.
+//
+// Returns:
+//
+//	The same Lifecycle, so that calls can be chained.
+func (lifecycle *Lifecycle[S]) Allow(from S, transitions ...Transition[S]) *Lifecycle[S] {
+	lifecycle.transitions[from] = append(lifecycle.transitions[from], transitions...)
+
+	return lifecycle
+}
+
+// Transitions returns the transitions that are allowed from a state, in the order in which they were declared.
+//
+// Parameters:
+//
+//	from - The current state.
+//
+// Returns:
+//
+//	The allowed transitions, which is empty if the state has none.
+func (lifecycle *Lifecycle[S]) Transitions(from S) []Transition[S] {
+	return append([]Transition[S]{}, lifecycle.transitions[from]...)
+}
+
+// Apply adds the links and templates of every transition allowed from the current state to a resource. Links whose
+// href still contains expressions after expansion are marked as templated.
+//
+// Parameters:
+//
+//	resource - A pointer to the Resource to which the affordances should be added.
+//	current - The current state of the resource.
+//	vars - The variables used to expand the link hrefs and template targets.
+//
+// Example:
+//
+//	// Advertise the transitions available to the order in its current state.
+//	halResource := gohalforms.NewResource(order)
+//	orders.Apply(&halResource, order.Status, map[string]any{"id": order.ID})
+func (lifecycle *Lifecycle[S]) Apply(resource *Resource, current S, vars map[string]any) {
+	for _, transition := range lifecycle.transitions[current] {
+		link, template := transition.expand(vars)
+
+		if template == nil {
+			resource.AddLink(transition.Rel, link)
+		} else {
+			resource.AddAffordance(transition.Rel, link, *template)
+		}
+	}
+}
+
+// Check determines whether an incoming request corresponds to a transition allowed from the current state, by matching
+// the request method and path against the link and template of each allowed transition.
+//
+// Parameters:
+//
+//	current - The current state of the resource.
+//	r - The incoming HTTP request.
+//	vars - The variables used to expand the link hrefs and template targets.
+//
+// Returns:
+//
+//	The matching transition, or an error wrapping ErrTransitionNotAllowed if there is none.
+//
+// Example:
+//
+//	// Reject attempts to approve an order that has not been submitted.
+//	transition, err := orders.Check(order.Status, r, map[string]any{"id": order.ID})
+//	if errors.Is(err, gohalforms.ErrTransitionNotAllowed) {
+//	    // Respond with a 409 Conflict.
+//	}
+//
+//	order.Status = transition.To
+func (lifecycle *Lifecycle[S]) Check(current S, r *http.Request, vars map[string]any) (Transition[S], error) {
+	for _, transition := range lifecycle.transitions[current] {
+		link, template := transition.expand(vars)
+
+		method, target := http.MethodGet, link.Href
+		if template != nil {
+			method, target = template.effectiveMethod(), template.Target
+		}
+
+		if strings.EqualFold(method, r.Method) && samePath(target, r.URL) {
+			return transition, nil
+		}
+	}
+
+	return Transition[S]{}, fmt.Errorf("%w: %s %s from state %v", ErrTransitionNotAllowed, r.Method, r.URL.Path, current)
+}
+
+// expand returns the link and template of the transition with their expressions expanded from the variables.
+func (transition Transition[S]) expand(vars map[string]any) (Link, *Template) {
+	link := transition.Link
+
+	link.Href, _, link.Templated = expandURI(link.Href, vars, true)
+
+	if transition.Template == nil {
+		return link, nil
+	}
+
+	template := *transition.Template
+	if template.Target == "" {
+		template.Target = link.Href
+	} else {
+		template.Target, _, _ = expandURI(template.Target, vars, true)
+	}
+
+	return link, &template
+}
+
+// samePath determines whether a target URL refers to the same path as a request URL.
+func samePath(target string, requested *url.URL) bool {
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+
+	return strings.TrimSuffix(parsed.EscapedPath(), "/") == strings.TrimSuffix(requested.EscapedPath(), "/")
+}
@@ -0,0 +1,118 @@
+package gohalforms_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kinbiko/jsonassert"
+	"github.com/sazzer/gohalforms"
+	"github.com/stretchr/testify/assert"
+)
+
+type orderState int
+
+const (
+	draft orderState = iota
+	submitted
+	approved
+)
+
+func orderLifecycle() *gohalforms.Lifecycle[orderState] {
+	return gohalforms.NewLifecycle[orderState]().
+		Allow(draft,
+			gohalforms.Transition[orderState]{
+				Rel:      "submit",
+				To:       submitted,
+				Link:     gohalforms.Link{Href: "/orders/{id}/submit", Title: "Submit"},
+				Template: &gohalforms.Template{Method: http.MethodPost},
+			},
+			gohalforms.Transition[orderState]{
+				Rel:      "edit",
+				To:       draft,
+				Link:     gohalforms.Link{Href: "/orders/{id}"},
+				Template: &gohalforms.Template{Method: http.MethodPut, Target: "/orders/{id}"},
+			},
+		).
+		Allow(submitted,
+			gohalforms.Transition[orderState]{
+				Rel:  "approve",
+				To:   approved,
+				Link: gohalforms.Link{Href: "/orders/{id}/approve"},
+			},
+		)
+}
+
+func TestLifecycleApply(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(nil)
+	orderLifecycle().Apply(&resource, draft, map[string]any{"id": "a b"})
+
+	encoded, err := gohalforms.Marshal(resource)
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"_links": {
+			"edit": {"href": "/orders/a%%20b"},
+			"submit": {"href": "/orders/a%%20b/submit", "title": "Submit"}
+		},
+		"_templates": {
+			"edit": {"method": "PUT", "target": "/orders/a%%20b", "properties": null},
+			"submit": {"method": "POST", "target": "/orders/a%%20b/submit", "title": "Submit", "properties": null}
+		}
+	}`)
+}
+
+func TestLifecycleApplyMissingVariables(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(nil)
+	orderLifecycle().Apply(&resource, submitted, nil)
+	orderLifecycle().Apply(&resource, approved, nil)
+
+	encoded, err := gohalforms.Marshal(resource)
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"_links": {
+			"approve": {"href": "/orders/{id}/approve", "templated": true}
+		}
+	}`)
+}
+
+func TestLifecycleTransitions(t *testing.T) {
+	t.Parallel()
+
+	lifecycle := orderLifecycle()
+
+	transitions := lifecycle.Transitions(draft)
+	assert.Len(t, transitions, 2)
+	assert.Equal(t, "submit", transitions[0].Rel)
+	assert.Equal(t, "edit", transitions[1].Rel)
+
+	assert.Empty(t, lifecycle.Transitions(approved))
+}
+
+func TestLifecycleCheck(t *testing.T) {
+	t.Parallel()
+
+	lifecycle := orderLifecycle()
+	vars := map[string]any{"id": 5}
+
+	transition, err := lifecycle.Check(draft, httptest.NewRequest(http.MethodPost, "/orders/5/submit", nil), vars)
+	assert.NoError(t, err)
+	assert.Equal(t, submitted, transition.To)
+
+	transition, err = lifecycle.Check(submitted, httptest.NewRequest(http.MethodGet, "/orders/5/approve/", nil), vars)
+	assert.NoError(t, err)
+	assert.Equal(t, approved, transition.To)
+
+	_, err = lifecycle.Check(submitted, httptest.NewRequest(http.MethodPost, "/orders/5/submit", nil), vars)
+	assert.ErrorIs(t, err, gohalforms.ErrTransitionNotAllowed)
+
+	_, err = lifecycle.Check(draft, httptest.NewRequest(http.MethodGet, "/orders/5/submit", nil), vars)
+	assert.ErrorIs(t, err, gohalforms.ErrTransitionNotAllowed)
+}
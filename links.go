@@ -12,13 +12,17 @@ type Link struct {
 	Profile     string `json:"profile,omitempty"`
 	Title       string `json:"title,omitempty"`
 	HrefLang    string `json:"hreflang,omitempty"`
+	// DeprecationDate is when the link was deprecated, in RFC 3339 format. It is set by Deprecate, and is not part of HAL.
+	DeprecationDate string `json:"deprecationDate,omitempty"`
+	// Sunset is when the link is expected to stop working, in RFC 3339 format. It is set by Deprecate, and is not part of HAL.
+	Sunset string `json:"sunset,omitempty"`
 }
 
 // links is a slice of Link instances used to represent multiple links within a HAL resource.
 type links []Link
 
-// linkset is a map where the keys represent relation names, and the values are slices of Link instances.
-type linkset map[string]links
+// linkset is an ordered map where the keys represent relation names, and the values are slices of Link instances.
+type linkset = relset[links]
 
 // MarshalJSON serializes a links slice to JSON. If there is only one Link in the slice, it is serialized individually.
 //
@@ -0,0 +1,238 @@
+package gohalforms
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Format is an output format that a resource can be encoded in, chosen using content negotiation.
+type Format struct {
+	// mediaTypes are the media types that select the format when they appear in an Accept header.
+	mediaTypes []string
+	// contentType returns the value of the Content-Type header for a resource in this format.
+	contentType func(resource Resource) string
+	// marshal encodes a resource in this format.
+	marshal func(resource Resource, options ...EncodeOption) ([]byte, error)
+	// represents determines whether a resource can be encoded in this format. If it is nil, every resource can be.
+	represents func(resource Resource) bool
+}
+
+// formats are the supported output formats. The first is used when the client accepts anything, or nothing that is
+// supported.
+var formats = []Format{
+	{
+		mediaTypes:  []string{"application/prs.hal-forms+json", "application/hal+json", "application/json"},
+		contentType: Resource.GetContentType,
+		marshal:     Marshal,
+	},
+	{
+		mediaTypes:  []string{SirenContentType},
+		contentType: func(Resource) string { return SirenContentType },
+		marshal:     MarshalSiren,
+	},
+	{
+		mediaTypes:  []string{JSONAPIContentType},
+		contentType: func(Resource) string { return JSONAPIContentType },
+		marshal:     MarshalJSONAPI,
+		represents:  Resource.identified,
+	},
+	{
+		mediaTypes:  []string{CollectionJSONContentType},
+		contentType: func(Resource) string { return CollectionJSONContentType },
+		marshal:     MarshalCollectionJSON,
+		represents:  Resource.listsItems,
+	},
+	{
+		mediaTypes:  []string{HALXMLContentType},
+		contentType: func(Resource) string { return HALXMLContentType },
+		marshal:     MarshalHALXML,
+	},
+}
+
+// ContentType returns the value of the Content-Type header for a resource encoded in this format.
+func (format Format) ContentType(resource Resource) string {
+	return format.contentType(resource)
+}
+
+// Marshal encodes a resource in this format, applying the options as described by EncodeOption.
+//
+// Parameters:
+//
+//	resource - The Resource instance to encode.
+//	options - Any options to control the encoding.
+//
+// Returns:
+//
+//	The encoded resource, or an error if it could not be encoded.
+func (format Format) Marshal(resource Resource, options ...EncodeOption) ([]byte, error) {
+	return format.marshal(resource, options...)
+}
+
+// Negotiate chooses the output format that best matches the value of an Accept header, taking account of quality
+// values, from those that can represent the resource. Media ranges with a quality of zero exclude the formats they
+// match, so a wildcard does not select a format that the client has ruled out more specifically. HAL is chosen when
+// the header is empty, accepts anything, or accepts nothing that is supported. JSON:API can only represent resources
+// with an identity, so it is never chosen for resources without one, and Collection+JSON is never chosen for resources
+// whose payload it would drop.
+//
+// Parameters:
+//
+//	accept - The value of the Accept header of the request.
+//	resource - The Resource instance to be encoded.
+//
+// Returns:
+//
+//	The chosen Format.
+//
+// Example:
+//
+//	// Encode the resource in whichever format the client prefers.
+//	format := gohalforms.Negotiate(r.Header.Get("Accept"), halResource)
+//	encoded, err := format.Marshal(halResource)
+func Negotiate(accept string, resource Resource) Format {
+	ranges, exclusions := parseAccept(accept)
+
+	for _, mediaRange := range ranges {
+		for _, format := range formats {
+			if format.represents != nil && !format.represents(resource) {
+				continue
+			}
+
+			if format.matches(mediaRange) && !format.excluded(mediaRange, exclusions) {
+				return format
+			}
+		}
+	}
+
+	return formats[0]
+}
+
+// Respond sends a resource as an HTTP response, in the format chosen by content negotiation on the Accept header of
+// the request. Like Send, the resource is fully encoded before anything is written. The limits are enforced whichever
+// format is chosen, so clients cannot avoid them by asking for a different format.
+//
+// Parameters:
+//
+//	w - The http.ResponseWriter where the response will be written.
+//	r - The incoming HTTP request, whose Accept header chooses the format.
+//	resource - The Resource instance representing the resource to be sent as a response.
+//	options - Any options to control how the resource is encoded.
+//
+// Returns:
+//
+//	An error if there was an issue encoding and sending the response; otherwise, it returns nil.
+//
+// Example:
+//
+//	// Serve both HAL and Siren clients from the same handler.
+//	err := gohalforms.Respond(w, r, halResource)
+//	if err != nil {
+//	    // Handle the error, e.g., log it or send an alternative response.
+//	}
+func Respond(w http.ResponseWriter, r *http.Request, resource Resource, options ...EncodeOption) error {
+	format := Negotiate(r.Header.Get("Accept"), resource)
+
+	encoded, err := format.Marshal(resource, options...)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Add("content-type", format.ContentType(resource))
+	w.Header().Add("vary", "Accept")
+	addDeprecationHeaders(w, resource)
+
+	_, err = w.Write(append(encoded, '\n'))
+
+	return err
+}
+
+// parseAccept splits an Accept header into its acceptable media ranges, most preferred first, and the media ranges that
+// are excluded because their quality is zero.
+func parseAccept(accept string) (ranges []string, exclusions []string) {
+	type mediaRange struct {
+		value   string
+		quality float64
+	}
+
+	var acceptable []mediaRange
+
+	for _, entry := range strings.Split(accept, ",") {
+		parts := strings.Split(entry, ";")
+		value := strings.ToLower(strings.TrimSpace(parts[0]))
+		quality := 1.0
+
+		for _, param := range parts[1:] {
+			name, raw, found := strings.Cut(strings.TrimSpace(param), "=")
+			if found && strings.EqualFold(strings.TrimSpace(name), "q") {
+				if parsed, err := strconv.ParseFloat(strings.TrimSpace(raw), 64); err == nil {
+					quality = parsed
+				}
+			}
+		}
+
+		switch {
+		case value == "":
+		case quality > 0:
+			acceptable = append(acceptable, mediaRange{value: value, quality: quality})
+		default:
+			exclusions = append(exclusions, value)
+		}
+	}
+
+	sort.SliceStable(acceptable, func(i, j int) bool {
+		return acceptable[i].quality > acceptable[j].quality
+	})
+
+	ranges = make([]string, 0, len(acceptable))
+	for _, mediaRange := range acceptable {
+		ranges = append(ranges, mediaRange.value)
+	}
+
+	return ranges, exclusions
+}
+
+// matches determines whether a media range from an Accept header matches any of the media types of this format.
+func (format Format) matches(mediaRange string) bool {
+	for _, mediaType := range format.mediaTypes {
+		if mediaRangeMatches(mediaRange, mediaType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// excluded determines whether this format has been ruled out by an excluded media range that is more specific than
+// the media range it matched, such as "application/vnd.siren+json;q=0" alongside "*/*".
+func (format Format) excluded(mediaRange string, exclusions []string) bool {
+	for _, exclusion := range exclusions {
+		if specificity(exclusion) > specificity(mediaRange) && format.matches(exclusion) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// specificity ranks a media range by how specific it is, from "*/*" up to a full media type.
+func specificity(mediaRange string) int {
+	switch {
+	case mediaRange == "*/*":
+		return 0
+	case strings.HasSuffix(mediaRange, "/*"):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// mediaRangeMatches determines whether a media range from an Accept header, which may contain wildcards, matches a media type.
+func mediaRangeMatches(mediaRange string, mediaType string) bool {
+	if mediaRange == "*/*" || mediaRange == mediaType {
+		return true
+	}
+
+	return strings.HasSuffix(mediaRange, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(mediaRange, "*"))
+}
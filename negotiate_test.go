@@ -0,0 +1,154 @@
+package gohalforms_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kinbiko/jsonassert"
+	"github.com/sazzer/gohalforms"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(nil)
+	resource.AddLink("self", gohalforms.Link{Href: "/"})
+
+	identified := resource
+	identified.SetIdentity("roots", "1")
+
+	tests := []struct {
+		accept     string
+		identified bool
+		expected   string
+	}{
+		{accept: "", expected: "application/hal+json; charset=utf-8"},
+		{accept: "*/*", expected: "application/hal+json; charset=utf-8"},
+		{accept: "application/hal+json", expected: "application/hal+json; charset=utf-8"},
+		{accept: "application/vnd.siren+json", expected: "application/vnd.siren+json"},
+		{accept: "application/vnd.collection+json", expected: "application/vnd.collection+json"},
+		{accept: "application/hal+xml", expected: "application/hal+xml"},
+		{accept: "application/xml;q=0.5, application/hal+xml", expected: "application/hal+xml"},
+		{accept: "application/vnd.siren+json;q=0.5, application/hal+json", expected: "application/hal+json; charset=utf-8"},
+		{accept: "application/hal+json;q=0.5, application/vnd.siren+json", expected: "application/vnd.siren+json"},
+		{accept: "application/*", expected: "application/hal+json; charset=utf-8"},
+		{accept: "text/html", expected: "application/hal+json; charset=utf-8"},
+		{accept: "application/hal+json;q=0, application/vnd.siren+json;q=0.1", expected: "application/vnd.siren+json"},
+		{accept: "application/vnd.siren+json;q=0, */*", expected: "application/hal+json; charset=utf-8"},
+		{accept: "application/hal+json;q=0, */*", expected: "application/vnd.siren+json"},
+		{accept: "application/hal+json;q=0, application/*", expected: "application/vnd.siren+json"},
+		{accept: "application/*;q=0, application/hal+xml", expected: "application/hal+xml"},
+		{accept: "application/vnd.api+json", identified: true, expected: "application/vnd.api+json"},
+		{accept: "application/vnd.api+json", expected: "application/hal+json; charset=utf-8"},
+		{accept: "application/vnd.api+json, application/vnd.siren+json;q=0.5", expected: "application/vnd.siren+json"},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(fmt.Sprintf("%s/%t", test.accept, test.identified), func(t *testing.T) {
+			t.Parallel()
+
+			resource := resource
+			if test.identified {
+				resource = identified
+			}
+
+			assert.Equal(t, test.expected, gohalforms.Negotiate(test.accept, resource).ContentType(resource))
+		})
+	}
+}
+
+func TestNegotiateCollectionJSON(t *testing.T) {
+	t.Parallel()
+
+	plain := gohalforms.NewResource(map[string]any{"name": "Graham"})
+	plain.AddLink("self", gohalforms.Link{Href: "/users/1"})
+	assert.Equal(t, "application/hal+json; charset=utf-8",
+		gohalforms.Negotiate("application/vnd.collection+json", plain).ContentType(plain))
+
+	users := gohalforms.NewResource(map[string]any{"total": 1})
+	users.AddLink("self", gohalforms.Link{Href: "/users"})
+	users.AddEmbedded("users", plain)
+	assert.Equal(t, "application/vnd.collection+json",
+		gohalforms.Negotiate("application/vnd.collection+json", users).ContentType(users))
+}
+
+func TestRespondSiren(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(map[string]any{"hello": "World!"})
+	resource.AddLink("self", gohalforms.Link{Href: "/"})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "application/vnd.siren+json")
+
+	rec := httptest.NewRecorder()
+	assert.NoError(t, gohalforms.Respond(rec, r, resource))
+
+	response := rec.Result()
+	defer response.Body.Close()
+
+	assert.Equal(t, "application/vnd.siren+json", response.Header.Get("content-type"))
+	assert.Equal(t, "Accept", response.Header.Get("vary"))
+
+	body, err := io.ReadAll(response.Body)
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(body), `{
+		"properties": {"hello": "World!"},
+		"links": [{"rel": ["self"], "href": "/"}]
+	}`)
+}
+
+func TestRespondHAL(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(map[string]any{"hello": "World!"})
+
+	rec := httptest.NewRecorder()
+	assert.NoError(t, gohalforms.Respond(rec, httptest.NewRequest(http.MethodGet, "/", nil), resource))
+
+	response := rec.Result()
+	defer response.Body.Close()
+
+	assert.Equal(t, "application/json; charset=utf-8", response.Header.Get("content-type"))
+
+	body, err := io.ReadAll(response.Body)
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(body), `{"hello": "World!"}`)
+}
+
+func TestRespondLimits(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(nil)
+	resource.AddEmbedded("items", gohalforms.NewResource(map[string]any{"index": 1}))
+	resource.AddEmbedded("items", gohalforms.NewResource(map[string]any{"index": 2}))
+	resource.AddEmbedded("items", gohalforms.NewResource(map[string]any{"index": 3}))
+
+	accepts := []string{
+		"application/hal+json",
+		"application/vnd.siren+json",
+		"application/vnd.collection+json",
+		"application/hal+xml",
+	}
+
+	for _, accept := range accepts {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept", accept)
+
+		err := gohalforms.Respond(httptest.NewRecorder(), r, resource, gohalforms.WithLimits(gohalforms.Limits{MaxEmbedded: 2}))
+		assert.ErrorIs(t, err, gohalforms.ErrLimitExceeded, accept)
+
+		err = gohalforms.Respond(httptest.NewRecorder(), r, resource, gohalforms.WithLimits(gohalforms.Limits{MaxSize: 10}))
+		assert.ErrorIs(t, err, gohalforms.ErrLimitExceeded, accept)
+	}
+}
@@ -0,0 +1,352 @@
+package gohalforms
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// The names of the members holding the prompt and value of an option, when the option does not specify otherwise.
+const (
+	DefaultPromptField = "prompt"
+	DefaultValueField  = "value"
+)
+
+// ErrInvalidOption is returned when an option value cannot be encoded or decoded because it lacks a required member.
+var ErrInvalidOption = errors.New("invalid option value")
+
+// OptionFieldError describes an option value that is missing the member holding its value.
+type OptionFieldError struct {
+	// Index is the position of the option value in the list.
+	Index int
+	// Field is the name of the missing member.
+	Field string
+}
+
+// Error returns a description of the missing member.
+func (err OptionFieldError) Error() string {
+	return fmt.Sprintf("option value %d has no %q member", err.Index, err.Field)
+}
+
+// Unwrap allows an OptionFieldError to be matched against ErrInvalidOption.
+func (err OptionFieldError) Unwrap() error {
+	return ErrInvalidOption
+}
+
+// Fields returns the names of the members holding the prompt and value of each option value, applying the defaults
+// from the HAL-FORMS specification.
+func (option LinkOption) Fields() (string, string) {
+	return optionFields(option.PromptField, option.ValueField)
+}
+
+// Fields returns the names of the members holding the prompt and value of each inline value, applying the defaults
+// from the HAL-FORMS specification.
+func (option InlineOption) Fields() (string, string) {
+	return optionFields(option.PromptField, option.ValueField)
+}
+
+// String returns the value as it is submitted by a client, such as in a query string or in selectedValues.
+func (value InlineOptionValue) String() string {
+	switch typed := value.Value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typed
+	}
+
+	encoded, err := json.Marshal(value.Value)
+	if err != nil {
+		return fmt.Sprint(value.Value)
+	}
+
+	return string(encoded)
+}
+
+// contains determines whether a value is one of the inline options.
+func (option InlineOption) contains(value string) bool {
+	for _, candidate := range option.Inline {
+		if candidate.String() == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// plain determines whether the inline values can be encoded as a plain string array.
+func (option InlineOption) plain() bool {
+	if option.PromptField != "" || option.ValueField != "" {
+		return false
+	}
+
+	for _, value := range option.Inline {
+		if _, ok := value.Value.(string); !ok || value.Prompt != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// inlineOptionJSON is the encoded form of an InlineOption.
+type inlineOptionJSON struct {
+	Inline         []json.RawMessage `json:"inline"`
+	PromptField    string            `json:"promptField,omitempty"`
+	ValueField     string            `json:"valueField,omitempty"`
+	MaxItems       uint32            `json:"maxItems,omitempty"`
+	MinItems       uint32            `json:"minItems,omitempty"`
+	SelectedValues []string          `json:"selectedValues,omitempty"`
+}
+
+// MarshalJSON serializes the inline option to JSON, either as a plain string array or as objects whose prompt and
+// value members are named by the PromptField and ValueField.
+func (option InlineOption) MarshalJSON() ([]byte, error) {
+	promptField, valueField := option.Fields()
+	plain := option.plain()
+
+	inline := make([]json.RawMessage, 0, len(option.Inline))
+
+	for _, value := range option.Inline {
+		var (
+			encoded []byte
+			err     error
+		)
+
+		if plain {
+			encoded, err = json.Marshal(value.Value)
+		} else {
+			encoded, err = marshalOptionValue(value, promptField, valueField)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		inline = append(inline, encoded)
+	}
+
+	return json.Marshal(inlineOptionJSON{
+		Inline:         inline,
+		PromptField:    option.PromptField,
+		ValueField:     option.ValueField,
+		MaxItems:       option.MaxItems,
+		MinItems:       option.MinItems,
+		SelectedValues: option.SelectedValues,
+	})
+}
+
+// UnmarshalJSON deserializes an inline option from JSON, accepting plain strings and objects whose prompt and value
+// members are named by the promptField and valueField.
+func (option *InlineOption) UnmarshalJSON(data []byte) error {
+	var decoded inlineOptionJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	promptField, valueField := optionFields(decoded.PromptField, decoded.ValueField)
+	inline := make([]InlineOptionValue, 0, len(decoded.Inline))
+
+	for index, encoded := range decoded.Inline {
+		value, err := unmarshalOptionValue(encoded, index, promptField, valueField)
+		if err != nil {
+			return err
+		}
+
+		inline = append(inline, value)
+	}
+
+	*option = InlineOption{
+		Inline:         inline,
+		PromptField:    decoded.PromptField,
+		ValueField:     decoded.ValueField,
+		MaxItems:       decoded.MaxItems,
+		MinItems:       decoded.MinItems,
+		SelectedValues: decoded.SelectedValues,
+	}
+
+	return nil
+}
+
+// optionFields applies the default names to the prompt and value fields of an option.
+func optionFields(promptField string, valueField string) (string, string) {
+	if promptField == "" {
+		promptField = DefaultPromptField
+	}
+
+	if valueField == "" {
+		valueField = DefaultValueField
+	}
+
+	return promptField, valueField
+}
+
+// marshalOptionValue encodes a single inline value as an object, omitting the prompt if it is empty.
+func marshalOptionValue(value InlineOptionValue, promptField string, valueField string) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.WriteByte('{')
+
+	if value.Prompt != "" {
+		key, _ := json.Marshal(promptField)
+		prompt, _ := json.Marshal(value.Prompt)
+
+		buffer.Write(key)
+		buffer.WriteByte(':')
+		buffer.Write(prompt)
+		buffer.WriteByte(',')
+	}
+
+	key, _ := json.Marshal(valueField)
+
+	encoded, err := json.Marshal(value.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	buffer.Write(key)
+	buffer.WriteByte(':')
+	buffer.Write(encoded)
+	buffer.WriteByte('}')
+
+	return buffer.Bytes(), nil
+}
+
+// unmarshalOptionValue decodes a single inline value from either a plain string or an object.
+func unmarshalOptionValue(encoded json.RawMessage, index int, promptField string, valueField string) (InlineOptionValue, error) {
+	var text string
+	if json.Unmarshal(encoded, &text) == nil {
+		return InlineOptionValue{Value: text}, nil
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &members); err != nil || members == nil {
+		return InlineOptionValue{}, OptionFieldError{Index: index, Field: valueField}
+	}
+
+	var result InlineOptionValue
+
+	if raw, ok := members[valueField]; !ok || string(raw) == "null" {
+		return InlineOptionValue{}, OptionFieldError{Index: index, Field: valueField}
+	} else if err := json.Unmarshal(raw, &result.Value); err != nil {
+		return InlineOptionValue{}, err
+	}
+
+	if raw, ok := members[promptField]; ok {
+		_ = json.Unmarshal(raw, &result.Prompt)
+	}
+
+	return result, nil
+}
+
+// MarshalOptions encodes a list of option values in the format expected at the target of a LinkOption.
+// Values that encode to JSON strings are used as both prompt and value. Any other value must encode to a JSON object
+// with a member named by the value field of the option; a member named by the prompt field is optional.
+//
+// Parameters:
+//
+//	option - The LinkOption whose target is serving the values, which determines the prompt and value fields.
+//	values - The option values, such as InlineOptionValue, strings or any struct that encodes to a JSON object.
+//
+// Returns:
+//
+//	The JSON array of option values, or an error if any of them could not be encoded or lack a value member.
+//
+// Example:
+//
+//	// Describe countries by their code and name.
+//	type country struct {
+//	    Code string `json:"code"`
+//	    Name string `json:"name"`
+//	}
+//
+//	option := gohalforms.LinkOption{Link: gohalforms.Link{Href: "/countries"}, PromptField: "name", ValueField: "code"}
+//	encoded, err := gohalforms.MarshalOptions(option, []country{{Code: "GB", Name: "United Kingdom"}})
+func MarshalOptions[T any](option LinkOption, values []T) ([]byte, error) {
+	_, valueField := option.Fields()
+
+	var buffer bytes.Buffer
+
+	buffer.WriteByte('[')
+
+	for index, value := range values {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := checkOption(encoded, index, valueField); err != nil {
+			return nil, err
+		}
+
+		if index > 0 {
+			buffer.WriteByte(',')
+		}
+
+		buffer.Write(encoded)
+	}
+
+	buffer.WriteByte(']')
+
+	return buffer.Bytes(), nil
+}
+
+// SendOptions sends a list of option values as an HTTP response, for use as the handler of the target of a LinkOption.
+//
+// Parameters:
+//
+//	w - The http.ResponseWriter where the response will be written.
+//	option - The LinkOption whose target is serving the values, which determines the prompt and value fields.
+//	values - The option values, such as InlineOptionValue, strings or any struct that encodes to a JSON object.
+//
+// Returns:
+//
+//	An error if there was an issue encoding and sending the response; otherwise, it returns nil.
+//
+// Example:
+//
+//	// Advertise the available colours on a property.
+//	colours := gohalforms.LinkOption{Link: gohalforms.Link{Href: "/colours"}}
+//
+//	// Serve them from the link target.
+//	err := gohalforms.SendOptions(w, colours, []gohalforms.InlineOptionValue{
+//	    {Prompt: "Red", Value: "red"},
+//	    {Prompt: "Blue", Value: "blue"},
+//	})
+func SendOptions[T any](w http.ResponseWriter, option LinkOption, values []T) error {
+	encoded, err := MarshalOptions(option, values)
+	if err != nil {
+		return err
+	}
+
+	contentType := option.Link.Type
+	if contentType == "" {
+		contentType = "application/json; charset=utf-8"
+	}
+
+	w.Header().Add("content-type", contentType)
+
+	_, err = w.Write(append(encoded, '\n'))
+
+	return err
+}
+
+// checkOption ensures that a single encoded option value is either a string or an object with a value member.
+func checkOption(encoded []byte, index int, valueField string) error {
+	var text string
+	if json.Unmarshal(encoded, &text) == nil {
+		return nil
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &members); err != nil || members == nil {
+		return OptionFieldError{Index: index, Field: valueField}
+	}
+
+	if value, ok := members[valueField]; !ok || string(value) == "null" {
+		return OptionFieldError{Index: index, Field: valueField}
+	}
+
+	return nil
+}
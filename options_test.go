@@ -0,0 +1,251 @@
+package gohalforms_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kinbiko/jsonassert"
+	"github.com/sazzer/gohalforms"
+	"github.com/stretchr/testify/assert"
+)
+
+type country struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+func TestMarshalLinkOptionFields(t *testing.T) {
+	t.Parallel()
+
+	encoded, err := json.Marshal(gohalforms.LinkOption{
+		Link:        gohalforms.Link{Href: "/countries"},
+		PromptField: "name",
+		ValueField:  "code",
+	})
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"link": {"href": "/countries"},
+		"promptField": "name",
+		"valueField": "code"
+	}`)
+}
+
+func TestLinkOptionFieldsDefaults(t *testing.T) {
+	t.Parallel()
+
+	promptField, valueField := gohalforms.LinkOption{}.Fields()
+	assert.Equal(t, "prompt", promptField)
+	assert.Equal(t, "value", valueField)
+
+	promptField, valueField = gohalforms.LinkOption{PromptField: "name", ValueField: "code"}.Fields()
+	assert.Equal(t, "name", promptField)
+	assert.Equal(t, "code", valueField)
+}
+
+func TestSendOptionValues(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	err := gohalforms.SendOptions(rec, gohalforms.LinkOption{}, []gohalforms.InlineOptionValue{
+		{Prompt: "Red", Value: "red"},
+		{Prompt: "Blue", Value: "blue"},
+	})
+	assert.NoError(t, err)
+
+	response := rec.Result()
+	defer response.Body.Close()
+
+	assert.Equal(t, []string{"application/json; charset=utf-8"}, response.Header.Values("content-type"))
+
+	body, err := io.ReadAll(response.Body)
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(body), `[
+		{"prompt": "Red", "value": "red"},
+		{"prompt": "Blue", "value": "blue"}
+	]`)
+}
+
+func TestSendOptionsMappedStructs(t *testing.T) {
+	t.Parallel()
+
+	option := gohalforms.LinkOption{
+		Link:        gohalforms.Link{Href: "/countries", Type: "application/vnd.countries+json"},
+		PromptField: "name",
+		ValueField:  "code",
+	}
+
+	rec := httptest.NewRecorder()
+	err := gohalforms.SendOptions(rec, option, []country{
+		{Code: "GB", Name: "United Kingdom"},
+		{Code: "FR", Name: "France"},
+	})
+	assert.NoError(t, err)
+
+	response := rec.Result()
+	defer response.Body.Close()
+
+	assert.Equal(t, []string{"application/vnd.countries+json"}, response.Header.Values("content-type"))
+
+	body, err := io.ReadAll(response.Body)
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(body), `[
+		{"code": "GB", "name": "United Kingdom"},
+		{"code": "FR", "name": "France"}
+	]`)
+}
+
+func TestMarshalOptionsStrings(t *testing.T) {
+	t.Parallel()
+
+	encoded, err := gohalforms.MarshalOptions(gohalforms.LinkOption{}, []string{"red", "blue"})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `["red", "blue"]`, string(encoded))
+
+	encoded, err = gohalforms.MarshalOptions(gohalforms.LinkOption{}, []string{})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[]`, string(encoded))
+}
+
+func TestMarshalOptionsMissingValueField(t *testing.T) {
+	t.Parallel()
+
+	option := gohalforms.LinkOption{ValueField: "id"}
+
+	_, err := gohalforms.MarshalOptions(option, []country{{Code: "GB", Name: "United Kingdom"}})
+	assert.ErrorIs(t, err, gohalforms.ErrInvalidOption)
+
+	var fieldError gohalforms.OptionFieldError
+	assert.ErrorAs(t, err, &fieldError)
+	assert.Equal(t, gohalforms.OptionFieldError{Index: 0, Field: "id"}, fieldError)
+
+	_, err = gohalforms.MarshalOptions(option, []int{1})
+	assert.ErrorIs(t, err, gohalforms.ErrInvalidOption)
+}
+
+func TestMarshalInlineOptionForms(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		option   gohalforms.InlineOption
+		expected string
+	}{
+		{
+			name: "Plain strings",
+			option: gohalforms.InlineOption{Inline: []gohalforms.InlineOptionValue{
+				{Value: "red"},
+				{Value: "blue"},
+			}},
+			expected: `{"inline": ["red", "blue"]}`,
+		},
+		{
+			name: "Prompts and values",
+			option: gohalforms.InlineOption{Inline: []gohalforms.InlineOptionValue{
+				{Prompt: "Red", Value: "red"},
+				{Value: "blue"},
+			}},
+			expected: `{"inline": [{"prompt": "Red", "value": "red"}, {"value": "blue"}]}`,
+		},
+		{
+			name: "Typed values",
+			option: gohalforms.InlineOption{
+				Inline: []gohalforms.InlineOptionValue{
+					{Prompt: "One", Value: 1},
+					{Prompt: "Half", Value: 0.5},
+					{Prompt: "Yes", Value: true},
+				},
+				SelectedValues: []string{"1"},
+			},
+			expected: `{
+				"inline": [
+					{"prompt": "One", "value": 1},
+					{"prompt": "Half", "value": 0.5},
+					{"prompt": "Yes", "value": true}
+				],
+				"selectedValues": ["1"]
+			}`,
+		},
+		{
+			name: "Mapped fields",
+			option: gohalforms.InlineOption{
+				Inline: []gohalforms.InlineOptionValue{
+					{Prompt: "United Kingdom", Value: "GB"},
+				},
+				PromptField: "name",
+				ValueField:  "code",
+				MaxItems:    1,
+			},
+			expected: `{
+				"inline": [{"name": "United Kingdom", "code": "GB"}],
+				"promptField": "name",
+				"valueField": "code",
+				"maxItems": 1
+			}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			encoded, err := json.Marshal(test.option)
+			assert.NoError(t, err)
+			assert.JSONEq(t, test.expected, string(encoded))
+
+			var decoded gohalforms.InlineOption
+			assert.NoError(t, json.Unmarshal(encoded, &decoded))
+
+			reencoded, err := json.Marshal(decoded)
+			assert.NoError(t, err)
+			assert.JSONEq(t, test.expected, string(reencoded))
+		})
+	}
+}
+
+func TestUnmarshalInlineOption(t *testing.T) {
+	t.Parallel()
+
+	var option gohalforms.InlineOption
+
+	err := json.Unmarshal([]byte(`{
+		"inline": ["red", {"name": "Blue", "code": 2}],
+		"promptField": "name",
+		"valueField": "code",
+		"minItems": 1,
+		"selectedValues": ["2"]
+	}`), &option)
+	assert.NoError(t, err)
+	assert.Equal(t, gohalforms.InlineOption{
+		Inline: []gohalforms.InlineOptionValue{
+			{Value: "red"},
+			{Prompt: "Blue", Value: 2.0},
+		},
+		PromptField:    "name",
+		ValueField:     "code",
+		MinItems:       1,
+		SelectedValues: []string{"2"},
+	}, option)
+
+	err = json.Unmarshal([]byte(`{"inline": [{"prompt": "Red"}]}`), &option)
+	assert.ErrorIs(t, err, gohalforms.ErrInvalidOption)
+}
+
+func TestInlineOptionValueString(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "red", gohalforms.InlineOptionValue{Value: "red"}.String())
+	assert.Equal(t, "42", gohalforms.InlineOptionValue{Value: 42}.String())
+	assert.Equal(t, "1.5", gohalforms.InlineOptionValue{Value: 1.5}.String())
+	assert.Equal(t, "true", gohalforms.InlineOptionValue{Value: true}.String())
+	assert.Equal(t, "", gohalforms.InlineOptionValue{}.String())
+}
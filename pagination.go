@@ -0,0 +1,265 @@
+package gohalforms
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// DefaultCursorParameter is the query string parameter that cursors are carried in unless configured otherwise.
+const DefaultCursorParameter = "cursor"
+
+// ErrInvalidCursor is returned when a cursor token is malformed or was not signed by the expected key.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ErrEmptyCursorKey is returned when a CursorCodec is created without a key, which would allow anyone to sign cursors.
+var ErrEmptyCursorKey = errors.New("cursor key is empty")
+
+// CursorCodec encodes and decodes opaque, signed cursor tokens used for cursor-based pagination.
+type CursorCodec struct {
+	key       []byte
+	parameter string
+}
+
+// CursorPage describes the positions either side of a page of results in a collection.
+type CursorPage struct {
+	// Next is the position to continue from for the following page, or nil if there is no more data.
+	Next any
+	// Prev is the position to continue from for the preceding page, or nil if this is the first page.
+	Prev any
+}
+
+// NewCursorCodec creates a new CursorCodec that signs cursors using the provided secret key.
+//
+// Parameters:
+//
+//	key - The secret key used to sign and verify cursor tokens. It must not be empty.
+//
+// Returns:
+//
+//	A CursorCodec that reads and writes cursors using the DefaultCursorParameter query string parameter, or
+//	ErrEmptyCursorKey if the key is empty.
+//
+// Example:
+//
+//	// Create a codec for signing cursors.
+//	codec, err := gohalforms.NewCursorCodec([]byte("super-secret-key"))
+func NewCursorCodec(key []byte) (CursorCodec, error) {
+	if len(key) == 0 {
+		return CursorCodec{}, ErrEmptyCursorKey
+	}
+
+	return CursorCodec{
+		key:       key,
+		parameter: DefaultCursorParameter,
+	}, nil
+}
+
+// WithParameter returns a copy of the CursorCodec that reads and writes cursors using the named query string parameter.
+//
+// Parameters:
+//
+//	name - The name of the query string parameter to use.
+//
+// Returns:
+//
+//	A new CursorCodec using the specified query string parameter.
+func (codec CursorCodec) WithParameter(name string) CursorCodec {
+	codec.parameter = name
+
+	return codec
+}
+
+// Parameter returns the name of the query string parameter that this CursorCodec uses.
+func (codec CursorCodec) Parameter() string {
+	return codec.parameter
+}
+
+// Encode converts a position within a collection into an opaque, signed cursor token.
+//
+// Parameters:
+//
+//	position - The position to encode. This can be any value that can be marshalled to JSON.
+//
+// Returns:
+//
+//	The cursor token, or an error if the position could not be encoded. ErrEmptyCursorKey is returned if the codec has
+//	no key, such as a zero CursorCodec.
+//
+// Example:
+//
+//	// Encode the ID of the last item on this page as a cursor.
+//	token, err := codec.Encode(lastItem.ID)
+func (codec CursorCodec) Encode(position any) (string, error) {
+	if len(codec.key) == 0 {
+		return "", ErrEmptyCursorKey
+	}
+
+	raw, err := json.Marshal(position)
+	if err != nil {
+		return "", err
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(raw)
+
+	return payload + "." + codec.sign(payload), nil
+}
+
+// Decode verifies a cursor token and converts it back into the position that it was created from.
+//
+// Parameters:
+//
+//	token - The cursor token to decode.
+//	position - A pointer to the value that the position should be decoded into.
+//
+// Returns:
+//
+//	An error wrapping ErrInvalidCursor if the token was malformed or not signed by this codec, or ErrEmptyCursorKey if the
+//	codec has no key; otherwise, it returns nil.
+//
+// Example:
+//
+//	// Decode a cursor back into the ID of the last item seen.
+//	var lastID int
+//	err := codec.Decode(token, &lastID)
+func (codec CursorCodec) Decode(token string, position any) error {
+	if len(codec.key) == 0 {
+		return ErrEmptyCursorKey
+	}
+
+	payload, signature, found := strings.Cut(token, ".")
+	if !found {
+		return fmt.Errorf("%w: missing signature", ErrInvalidCursor)
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(codec.sign(payload))) {
+		return fmt.Errorf("%w: signature mismatch", ErrInvalidCursor)
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	if err := json.Unmarshal(raw, position); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	return nil
+}
+
+// FromRequest reads and verifies the cursor from the query string of an incoming HTTP request.
+//
+// Parameters:
+//
+//	r - The incoming HTTP request.
+//	position - A pointer to the value that the position should be decoded into.
+//
+// Returns:
+//
+//	True if a cursor was present on the request, and an error wrapping ErrInvalidCursor if it could not be verified.
+//
+// Example:
+//
+//	// Read the cursor for the page to return, if there is one.
+//	var lastID int
+//	found, err := codec.FromRequest(r, &lastID)
+//	if err != nil {
+//	    // Respond with a 400 Bad Request.
+//	}
+func (codec CursorCodec) FromRequest(r *http.Request, position any) (bool, error) {
+	token := r.URL.Query().Get(codec.parameter)
+	if token == "" {
+		return false, nil
+	}
+
+	return true, codec.Decode(token, position)
+}
+
+// AddPageLinks adds "next" and "prev" links to a collection resource for the positions described by page.
+// Links are only added when there is more data in that direction, meaning that the position is not nil. A nil pointer,
+// slice or map stored in the position also counts as nil.
+//
+// Parameters:
+//
+//	resource - A pointer to the Resource instance to which the links should be added.
+//	base - The URL of the current page. Any existing query parameters are preserved in the generated links.
+//	page - The positions of the neighbouring pages.
+//
+// Returns:
+//
+//	An error if either position could not be encoded; otherwise, it returns nil.
+//
+// Example:
+//
+//	// Link to the next page of results, starting after the last item on this page.
+//	err := codec.AddPageLinks(&halResource, r.URL, gohalforms.CursorPage{
+//	    Next: lastItem.ID,
+//	})
+func (codec CursorCodec) AddPageLinks(resource *Resource, base *url.URL, page CursorPage) error {
+	if !isNil(page.Next) {
+		href, err := codec.href(base, page.Next)
+		if err != nil {
+			return err
+		}
+
+		resource.AddLink(RelNext, Link{Href: href})
+	}
+
+	if !isNil(page.Prev) {
+		href, err := codec.href(base, page.Prev)
+		if err != nil {
+			return err
+		}
+
+		resource.AddLink(RelPrev, Link{Href: href})
+	}
+
+	return nil
+}
+
+// isNil determines whether a position is nil, including a nil pointer, slice, map or similar stored in an interface.
+func isNil(position any) bool {
+	if position == nil {
+		return true
+	}
+
+	value := reflect.ValueOf(position)
+
+	switch value.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice:
+		return value.IsNil()
+	default:
+		return false
+	}
+}
+
+// href builds the URL for the page starting at the given position.
+func (codec CursorCodec) href(base *url.URL, position any) (string, error) {
+	token, err := codec.Encode(position)
+	if err != nil {
+		return "", err
+	}
+
+	target := *base
+	query := target.Query()
+	query.Set(codec.parameter, token)
+	target.RawQuery = query.Encode()
+
+	return target.String(), nil
+}
+
+// sign generates the signature for an encoded cursor payload.
+func (codec CursorCodec) sign(payload string) string {
+	mac := hmac.New(sha256.New, codec.key)
+	mac.Write([]byte(payload))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,217 @@
+package gohalforms_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/kinbiko/jsonassert"
+	"github.com/sazzer/gohalforms"
+	"github.com/stretchr/testify/assert"
+)
+
+func cursorCodec(t *testing.T, key string) gohalforms.CursorCodec {
+	t.Helper()
+
+	codec, err := gohalforms.NewCursorCodec([]byte(key))
+	assert.NoError(t, err)
+
+	return codec
+}
+
+func TestCursorEmptyKey(t *testing.T) {
+	t.Parallel()
+
+	for _, key := range [][]byte{nil, {}} {
+		_, err := gohalforms.NewCursorCodec(key)
+		assert.ErrorIs(t, err, gohalforms.ErrEmptyCursorKey)
+	}
+}
+
+func TestCursorZeroCodec(t *testing.T) {
+	t.Parallel()
+
+	var codec gohalforms.CursorCodec
+
+	_, err := codec.Encode(42)
+	assert.ErrorIs(t, err, gohalforms.ErrEmptyCursorKey)
+
+	token, err := cursorCodec(t, "secret").Encode(42)
+	assert.NoError(t, err)
+
+	var decoded int
+	assert.ErrorIs(t, codec.Decode(token, &decoded), gohalforms.ErrEmptyCursorKey)
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	type position struct {
+		ID    int    `json:"id"`
+		Title string `json:"title"`
+	}
+
+	codec := cursorCodec(t, "secret")
+
+	token, err := codec.Encode(position{ID: 42, Title: "Answer"})
+	assert.NoError(t, err)
+
+	var decoded position
+	err = codec.Decode(token, &decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, position{ID: 42, Title: "Answer"}, decoded)
+}
+
+func TestCursorTampered(t *testing.T) {
+	t.Parallel()
+
+	codec := cursorCodec(t, "secret")
+
+	token, err := codec.Encode(42)
+	assert.NoError(t, err)
+
+	payload, signature, _ := strings.Cut(token, ".")
+	forged, err := cursorCodec(t, "other").Encode(43)
+	assert.NoError(t, err)
+
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+
+	for _, token := range []string{
+		payload,
+		forgedPayload + "." + signature,
+		forged,
+		"",
+	} {
+		var decoded int
+		err = codec.Decode(token, &decoded)
+		assert.ErrorIs(t, err, gohalforms.ErrInvalidCursor, token)
+	}
+}
+
+func TestCursorFromRequest(t *testing.T) {
+	t.Parallel()
+
+	codec := cursorCodec(t, "secret").WithParameter("after")
+
+	token, err := codec.Encode(42)
+	assert.NoError(t, err)
+
+	var position int
+	found, err := codec.FromRequest(httptest.NewRequest(http.MethodGet, "/items?after="+token, nil), &position)
+	assert.True(t, found)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, position)
+
+	found, err = codec.FromRequest(httptest.NewRequest(http.MethodGet, "/items", nil), &position)
+	assert.False(t, found)
+	assert.NoError(t, err)
+
+	found, err = codec.FromRequest(httptest.NewRequest(http.MethodGet, "/items?after=bad", nil), &position)
+	assert.True(t, found)
+	assert.ErrorIs(t, err, gohalforms.ErrInvalidCursor)
+}
+
+func TestCursorPageLinks(t *testing.T) {
+	t.Parallel()
+
+	codec := cursorCodec(t, "secret")
+	base, err := url.Parse("/items?sort=name")
+	assert.NoError(t, err)
+
+	resource := gohalforms.NewResource(nil)
+	err = codec.AddPageLinks(&resource, base, gohalforms.CursorPage{Next: 10, Prev: 1})
+	assert.NoError(t, err)
+
+	next, err := codec.Encode(10)
+	assert.NoError(t, err)
+
+	prev, err := codec.Encode(1)
+	assert.NoError(t, err)
+
+	encoded, err := json.Marshal(resource)
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"_links": {
+			"next": {"href": "/items?cursor=%s&sort=name"},
+			"prev": {"href": "/items?cursor=%s&sort=name"}
+		}
+	}`, next, prev)
+}
+
+func TestCursorPageLinksNoMoreData(t *testing.T) {
+	t.Parallel()
+
+	codec := cursorCodec(t, "secret")
+	base, err := url.Parse("/items")
+	assert.NoError(t, err)
+
+	resource := gohalforms.NewResource(nil)
+	err = codec.AddPageLinks(&resource, base, gohalforms.CursorPage{})
+	assert.NoError(t, err)
+
+	encoded, err := json.Marshal(resource)
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{}`)
+}
+
+func TestCursorPageLinksTypedNil(t *testing.T) {
+	t.Parallel()
+
+	codec := cursorCodec(t, "secret")
+	base, err := url.Parse("/items")
+	assert.NoError(t, err)
+
+	var (
+		next *int
+		prev []string
+	)
+
+	resource := gohalforms.NewResource(nil)
+	err = codec.AddPageLinks(&resource, base, gohalforms.CursorPage{Next: next, Prev: prev})
+	assert.NoError(t, err)
+
+	encoded, err := json.Marshal(resource)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{}`, string(encoded))
+}
+
+func TestCursorPageLinksTruncated(t *testing.T) {
+	t.Parallel()
+
+	codec := cursorCodec(t, "secret")
+	base, err := url.Parse("/items")
+	assert.NoError(t, err)
+
+	resource := gohalforms.NewResource(nil)
+	for i := 0; i < 3; i++ {
+		resource.AddEmbedded("items", gohalforms.NewResource(map[string]any{"index": i}))
+	}
+
+	err = codec.AddPageLinks(&resource, base, gohalforms.CursorPage{Next: 3})
+	assert.NoError(t, err)
+
+	encoded, err := gohalforms.Marshal(resource, gohalforms.WithLimits(gohalforms.Limits{
+		MaxEmbedded: 2,
+		Truncate: func(rel string, kept int) gohalforms.Link {
+			return gohalforms.Link{Href: "/items?offset=2"}
+		},
+	}))
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"_links": {
+			"next": {"href": "/items?offset=2"}
+		},
+		"_embedded": {
+			"items": [{"index": 0}, {"index": 1}]
+		}
+	}`)
+}
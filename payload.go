@@ -0,0 +1,143 @@
+package gohalforms
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrPayloadNotObject is returned when the payload of a resource does not encode to a JSON object.
+var ErrPayloadNotObject = errors.New("payload is not a JSON object")
+
+// PayloadNotObjectError describes a resource payload that does not encode to a JSON object, and so cannot have the HAL
+// members added to it.
+type PayloadNotObjectError struct {
+	// Type is the Go type of the payload.
+	Type reflect.Type
+	// Location is a JSON Pointer to the resource whose payload is not an object.
+	Location string
+}
+
+// Error returns a description of the invalid payload.
+func (err PayloadNotObjectError) Error() string {
+	if err.Location == "" {
+		return fmt.Sprintf("payload of type %s is not a JSON object", err.Type)
+	}
+
+	return fmt.Sprintf("payload of type %s at %s is not a JSON object", err.Type, err.Location)
+}
+
+// Unwrap allows a PayloadNotObjectError to be matched against ErrPayloadNotObject.
+func (err PayloadNotObjectError) Unwrap() error {
+	return ErrPayloadNotObject
+}
+
+// WithPayloadWrapping configures payloads that do not encode to a JSON object, such as slices and scalars, to be wrapped
+// in an object under the named property instead of failing with a PayloadNotObjectError.
+//
+// Parameters:
+//
+//	name - The name of the property to wrap non-object payloads in.
+//
+// Returns:
+//
+//	An EncodeOption applying the wrapping.
+//
+// Example:
+//
+//	// Encode a list of names as {"values": ["a", "b"]}.
+//	halResource := gohalforms.NewResource([]string{"a", "b"})
+//	encoded, err := gohalforms.Marshal(halResource, gohalforms.WithPayloadWrapping("values"))
+func WithPayloadWrapping(name string) EncodeOption {
+	return func(options *encodeOptions) {
+		options.wrapPayload = name
+	}
+}
+
+// payloadObject holds the members of a payload encoded as a JSON object, without the surrounding braces, so that they
+// can be spliced directly into the encoded resource.
+type payloadObject []byte
+
+// member is a single member of a JSON object, with the value kept exactly as it was encoded.
+type member struct {
+	key   string
+	value json.RawMessage
+}
+
+// encodePayload marshals a payload and extracts the members of the resulting JSON object.
+// A nil payload, or one that encodes to null, has no members. Any other payload that is not an object is wrapped in an
+// object under the property named by wrap, or fails if that is empty.
+func encodePayload(payload any, wrap string, location string) (payloadObject, error) {
+	if payload == nil {
+		return nil, nil
+	}
+
+	// json.Marshal always produces compact output, so there is no surrounding whitespace to consider.
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if bytes.Equal(raw, []byte("null")) {
+		return nil, nil
+	}
+
+	if raw[0] != '{' {
+		if wrap == "" {
+			return nil, PayloadNotObjectError{Type: reflect.TypeOf(payload), Location: location}
+		}
+
+		return payloadObject{}.with(member{key: wrap, value: raw}), nil
+	}
+
+	return payloadObject(raw[1 : len(raw)-1]), nil
+}
+
+// members splits the payload into its individual members, in the order that they were encoded.
+func (payload payloadObject) members() ([]member, error) {
+	decoder := json.NewDecoder(bytes.NewReader(append(append([]byte{'{'}, payload...), '}')))
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+
+	members := []member{}
+
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+
+		members = append(members, member{key: key.(string), value: value})
+	}
+
+	return members, nil
+}
+
+// mightContain cheaply determines whether the payload could contain a member with the given key.
+// False positives are possible, for example if the key appears within a nested object or a string value.
+func (payload payloadObject) mightContain(key string) bool {
+	return bytes.Contains(payload, []byte(`"`+key+`"`))
+}
+
+// with returns the payload with an additional member appended.
+func (payload payloadObject) with(member member) payloadObject {
+	key, _ := json.Marshal(member.key)
+
+	if len(payload) > 0 {
+		payload = append(payload, ',')
+	}
+
+	payload = append(payload, key...)
+	payload = append(payload, ':')
+
+	return append(payload, member.value...)
+}
@@ -0,0 +1,75 @@
+package gohalforms_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/kinbiko/jsonassert"
+	"github.com/sazzer/gohalforms"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalNonObjectPayloadError(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(nil)
+	resource.AddEmbedded("item", gohalforms.NewResource([]string{"a", "b"}))
+
+	_, err := gohalforms.Marshal(resource)
+	assert.Equal(t, gohalforms.PayloadNotObjectError{
+		Type:     reflect.TypeOf([]string{}),
+		Location: "/_embedded/item",
+	}, err)
+	assert.EqualError(t, err, "payload of type []string at /_embedded/item is not a JSON object")
+
+	resource = gohalforms.NewResource(nil)
+	resource.AddEmbedded("item", gohalforms.NewResource(nil))
+	resource.AddEmbedded("item", gohalforms.NewResource([]string{"c"}))
+
+	_, err = gohalforms.Marshal(resource)
+	assert.EqualError(t, err, "payload of type []string at /_embedded/item/1 is not a JSON object")
+}
+
+func TestNewResourceChecked(t *testing.T) {
+	t.Parallel()
+
+	type body struct {
+		Hello string `json:"hello"`
+	}
+
+	for _, payload := range []any{nil, body{}, &body{}, map[string]any{}, (*body)(nil), json.RawMessage(`{"a":1}`)} {
+		_, err := gohalforms.NewResourceChecked(payload)
+		assert.NoError(t, err, "%T", payload)
+	}
+
+	for _, payload := range []any{1, "hello", true, []string{}, json.RawMessage(`[1]`)} {
+		_, err := gohalforms.NewResourceChecked(payload)
+		assert.ErrorIs(t, err, gohalforms.ErrPayloadNotObject, "%T", payload)
+		assert.Equal(t, gohalforms.PayloadNotObjectError{Type: reflect.TypeOf(payload)}, err)
+	}
+}
+
+func TestMarshalWrappedPayload(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource([]int{1, 2, 3})
+	resource.AddLink("self", gohalforms.Link{Href: "/numbers"})
+	resource.AddEmbedded("answer", gohalforms.NewResource(42))
+	resource.AddEmbedded("object", gohalforms.NewResource(map[string]any{"hello": "World!"}))
+
+	encoded, err := gohalforms.Marshal(resource, gohalforms.WithPayloadWrapping("value"))
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"_links": {
+			"self": {"href": "/numbers"}
+		},
+		"_embedded": {
+			"answer": {"value": 42},
+			"object": {"hello": "World!"}
+		},
+		"value": [1, 2, 3]
+	}`)
+}
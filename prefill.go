@@ -0,0 +1,146 @@
+package gohalforms
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Prefill returns a copy of the template with the properties populated from the current state of a resource, so that
+// an edit form starts with the existing values. Each property is matched to the payload member with the same name.
+// Names containing dots, such as "address.city", are matched against nested objects when there is no member with the
+// full name. Properties with options have their selected values set, and other properties have their value set.
+// Properties with no matching member, or whose member is null, are left unchanged.
+//
+// Parameters:
+//
+//	payload - The payload of the resource being edited, which must encode to a JSON object.
+//
+// Returns:
+//
+//	The populated Template, or an error if the payload could not be encoded as a JSON object.
+//
+// Example:
+//
+//	// Describe how to edit a user.
+//	edit := gohalforms.Template{
+//	    Method: http.MethodPut,
+//	    Properties: []gohalforms.Property{
+//	        gohalforms.TextProperty("name", gohalforms.Required()),
+//	        gohalforms.NumberProperty("age"),
+//	        gohalforms.TextProperty("address.city"),
+//	    },
+//	}
+//
+//	// Fill in the current values of the user.
+//	prefilled, err := edit.Prefill(user)
+//	if err != nil {
+//	    // Handle the error.
+//	}
+//
+//	halResource.AddTemplate("default", prefilled)
+func (template Template) Prefill(payload any) (Template, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return Template{}, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+
+	var members map[string]any
+	if err := decoder.Decode(&members); err != nil || members == nil {
+		return Template{}, PayloadNotObjectError{Type: reflect.TypeOf(payload)}
+	}
+
+	properties := make([]Property, 0, len(template.Properties))
+
+	for _, property := range template.Properties {
+		if value, ok := lookupMember(members, property.Name); ok {
+			property = property.prefill(value)
+		}
+
+		properties = append(properties, property)
+	}
+
+	template.Properties = properties
+
+	return template, nil
+}
+
+// prefill returns a copy of the property populated with the given decoded JSON value.
+func (property Property) prefill(value any) Property {
+	values, isList := value.([]any)
+	if !isList {
+		values = []any{value}
+	}
+
+	texts := make([]string, 0, len(values))
+
+	for _, value := range values {
+		if text, ok := property.valueText(value); ok {
+			texts = append(texts, text)
+		}
+	}
+
+	switch options := property.Options.(type) {
+	case InlineOption:
+		options.SelectedValues = texts
+		property.Options = options
+	case LinkOption:
+		options.SelectedValues = texts
+		property.Options = options
+	default:
+		if !isList && len(texts) == 1 {
+			property.Value = texts[0]
+		}
+	}
+
+	return property
+}
+
+// valueText converts a decoded JSON scalar to the text used as the value of the property. Timestamps are converted to
+// the format expected by date and time properties.
+func (property Property) valueText(value any) (string, bool) {
+	switch typed := value.(type) {
+	case string:
+		if property.Type.Temporal() {
+			if timestamp, err := time.Parse(time.RFC3339Nano, typed); err == nil {
+				return timestamp.Format(property.Type.layout()), true
+			}
+		}
+
+		return typed, true
+	case json.Number:
+		return typed.String(), true
+	case bool:
+		if typed {
+			return "true", true
+		}
+
+		return "false", true
+	default:
+		return "", false
+	}
+}
+
+// lookupMember finds the value of a member of a decoded JSON object by name, following dotted paths into nested objects.
+func lookupMember(members map[string]any, name string) (any, bool) {
+	if value, ok := members[name]; ok {
+		return value, value != nil
+	}
+
+	head, rest, found := strings.Cut(name, ".")
+	if !found {
+		return nil, false
+	}
+
+	nested, ok := members[head].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+
+	return lookupMember(nested, rest)
+}
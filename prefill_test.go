@@ -0,0 +1,109 @@
+package gohalforms_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sazzer/gohalforms"
+	"github.com/stretchr/testify/assert"
+)
+
+type address struct {
+	City     string `json:"city"`
+	Postcode string `json:"postcode"`
+}
+
+type user struct {
+	Name     string    `json:"name"`
+	Age      int64     `json:"age"`
+	Score    float64   `json:"score"`
+	Active   bool      `json:"active"`
+	Joined   time.Time `json:"joined"`
+	Colour   string    `json:"colour"`
+	Tags     []string  `json:"tags"`
+	Nickname *string   `json:"nickname"`
+	Address  address   `json:"address"`
+}
+
+func editTemplate() gohalforms.Template {
+	return gohalforms.Template{
+		Method: http.MethodPut,
+		Properties: []gohalforms.Property{
+			gohalforms.TextProperty("name"),
+			gohalforms.NumberProperty("age"),
+			gohalforms.NumberProperty("score"),
+			gohalforms.CheckboxProperty("active"),
+			gohalforms.DateProperty("joined"),
+			gohalforms.SelectProperty("colour", gohalforms.InlineOption{
+				Inline: []gohalforms.InlineOptionValue{{Value: "red"}, {Value: "blue"}},
+			}),
+			gohalforms.MultiSelectProperty("tags", gohalforms.LinkOption{Link: gohalforms.Link{Href: "/tags"}}),
+			gohalforms.TextProperty("nickname", gohalforms.DefaultValue("none")),
+			gohalforms.TextProperty("address.city"),
+			gohalforms.TextProperty("missing", gohalforms.DefaultValue("unchanged")),
+		},
+	}
+}
+
+func TestPrefillTemplateFromStruct(t *testing.T) {
+	t.Parallel()
+
+	template := editTemplate()
+
+	prefilled, err := template.Prefill(user{
+		Name:    "Graham",
+		Age:     9007199254740993,
+		Score:   4.5,
+		Active:  true,
+		Joined:  time.Date(2023, 10, 1, 12, 30, 0, 0, time.UTC),
+		Colour:  "blue",
+		Tags:    []string{"a", "b"},
+		Address: address{City: "London"},
+	})
+	assert.NoError(t, err)
+
+	values := map[string]string{}
+	for _, property := range prefilled.Properties {
+		values[property.Name] = property.Value
+	}
+
+	assert.Equal(t, map[string]string{
+		"name":         "Graham",
+		"age":          "9007199254740993",
+		"score":        "4.5",
+		"active":       "true",
+		"joined":       "2023-10-01",
+		"colour":       "",
+		"tags":         "",
+		"nickname":     "none",
+		"address.city": "London",
+		"missing":      "unchanged",
+	}, values)
+
+	assert.Equal(t, []string{"blue"}, prefilled.Properties[5].Options.(gohalforms.InlineOption).SelectedValues)
+	assert.Equal(t, []string{"a", "b"}, prefilled.Properties[6].Options.(gohalforms.LinkOption).SelectedValues)
+
+	// The original template is not modified.
+	assert.Equal(t, editTemplate(), template)
+}
+
+func TestPrefillTemplateFromMap(t *testing.T) {
+	t.Parallel()
+
+	prefilled, err := editTemplate().Prefill(map[string]any{
+		"name":         "Graham",
+		"address.city": "Paris",
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Graham", prefilled.Properties[0].Value)
+	assert.Equal(t, "Paris", prefilled.Properties[8].Value)
+}
+
+func TestPrefillTemplateNotObject(t *testing.T) {
+	t.Parallel()
+
+	_, err := editTemplate().Prefill([]string{"a"})
+	assert.ErrorIs(t, err, gohalforms.ErrPayloadNotObject)
+}
@@ -0,0 +1,545 @@
+package gohalforms
+
+import "time"
+
+// TextSetting configures a property created by TextProperty, EmailProperty, PasswordProperty, TelProperty or URLProperty.
+type TextSetting interface {
+	applyText(property *Property)
+}
+
+// TextAreaSetting configures a property created by TextAreaProperty.
+type TextAreaSetting interface {
+	applyTextArea(property *Property)
+}
+
+// NumberSetting configures a property created by NumberProperty or RangeProperty.
+type NumberSetting interface {
+	applyNumber(property *Property)
+}
+
+// DateSetting configures a property created by DateProperty or DateTimeProperty.
+type DateSetting interface {
+	applyDate(property *Property)
+}
+
+// CheckboxSetting configures a property created by CheckboxProperty.
+type CheckboxSetting interface {
+	applyCheckbox(property *Property)
+}
+
+// SelectSetting configures a property created by SelectProperty.
+type SelectSetting interface {
+	applySelect(property *Property)
+}
+
+// MultiSelectSetting configures a property created by MultiSelectProperty.
+type MultiSelectSetting interface {
+	applyMultiSelect(property *Property)
+}
+
+// CommonSetting is a setting that is meaningful for every kind of property.
+type CommonSetting func(property *Property)
+
+func (setting CommonSetting) applyText(property *Property)        { setting(property) }
+func (setting CommonSetting) applyTextArea(property *Property)    { setting(property) }
+func (setting CommonSetting) applyNumber(property *Property)      { setting(property) }
+func (setting CommonSetting) applyDate(property *Property)        { setting(property) }
+func (setting CommonSetting) applyCheckbox(property *Property)    { setting(property) }
+func (setting CommonSetting) applySelect(property *Property)      { setting(property) }
+func (setting CommonSetting) applyMultiSelect(property *Property) { setting(property) }
+
+// FreeTextSetting is a setting that is meaningful for properties that are typed in by the user.
+type FreeTextSetting func(property *Property)
+
+func (setting FreeTextSetting) applyText(property *Property)     { setting(property) }
+func (setting FreeTextSetting) applyTextArea(property *Property) { setting(property) }
+func (setting FreeTextSetting) applyNumber(property *Property)   { setting(property) }
+
+// LengthSetting is a setting that is meaningful for text properties.
+type LengthSetting func(property *Property)
+
+func (setting LengthSetting) applyText(property *Property)     { setting(property) }
+func (setting LengthSetting) applyTextArea(property *Property) { setting(property) }
+
+// TextAreaLayoutSetting is a setting that is only meaningful for textarea properties.
+type TextAreaLayoutSetting func(property *Property)
+
+func (setting TextAreaLayoutSetting) applyTextArea(property *Property) { setting(property) }
+
+// NumberBoundSetting is a setting that is only meaningful for number and range properties.
+type NumberBoundSetting func(property *Property)
+
+func (setting NumberBoundSetting) applyNumber(property *Property) { setting(property) }
+
+// DateBoundSetting is a setting that is only meaningful for date and datetime-local properties.
+type DateBoundSetting func(property *Property)
+
+func (setting DateBoundSetting) applyDate(property *Property) { setting(property) }
+
+// SelectionSetting is a setting that is meaningful for properties with options to choose from.
+type SelectionSetting func(property *Property)
+
+func (setting SelectionSetting) applySelect(property *Property)      { setting(property) }
+func (setting SelectionSetting) applyMultiSelect(property *Property) { setting(property) }
+
+// ItemsSetting is a setting that is only meaningful for properties where several options can be chosen.
+type ItemsSetting func(property *Property)
+
+func (setting ItemsSetting) applyMultiSelect(property *Property) { setting(property) }
+
+// Required marks a property as requiring a value.
+func Required() CommonSetting {
+	return func(property *Property) {
+		property.Required = true
+	}
+}
+
+// ReadOnly marks a property as not being editable.
+func ReadOnly() CommonSetting {
+	return func(property *Property) {
+		property.Readonly = true
+	}
+}
+
+// Prompt sets the human-readable prompt for a property.
+func Prompt(prompt string) CommonSetting {
+	return func(property *Property) {
+		property.Prompt = prompt
+	}
+}
+
+// DefaultValue sets the initial value of a property.
+func DefaultValue(value string) CommonSetting {
+	return func(property *Property) {
+		property.Value = value
+	}
+}
+
+// Placeholder sets the hint displayed in a property before a value is entered.
+func Placeholder(placeholder string) FreeTextSetting {
+	return func(property *Property) {
+		property.Placeholder = placeholder
+	}
+}
+
+// MinLength sets the minimum number of characters in the value of a text property.
+func MinLength(length uint32) LengthSetting {
+	return func(property *Property) {
+		property.MinLength = length
+	}
+}
+
+// MaxLength sets the maximum number of characters in the value of a text property.
+func MaxLength(length uint32) LengthSetting {
+	return func(property *Property) {
+		property.MaxLength = length
+	}
+}
+
+// Pattern sets the regular expression that the whole value of a text property must match.
+func Pattern(regex string) LengthSetting {
+	return func(property *Property) {
+		property.Regex = regex
+	}
+}
+
+// Rows sets the number of visible lines in a textarea property.
+func Rows(rows uint32) TextAreaLayoutSetting {
+	return func(property *Property) {
+		property.Rows = rows
+	}
+}
+
+// Cols sets the visible width, in characters, of a textarea property.
+func Cols(cols uint32) TextAreaLayoutSetting {
+	return func(property *Property) {
+		property.Cols = cols
+	}
+}
+
+// Min sets the minimum value of a number or range property.
+func Min(value float64) NumberBoundSetting {
+	return func(property *Property) {
+		property.Min = NumberBound(value)
+	}
+}
+
+// Max sets the maximum value of a number or range property.
+func Max(value float64) NumberBoundSetting {
+	return func(property *Property) {
+		property.Max = NumberBound(value)
+	}
+}
+
+// Step sets the granularity of the value of a number or range property.
+func Step(value float64) NumberBoundSetting {
+	return func(property *Property) {
+		property.Step = value
+	}
+}
+
+// MinDate sets the earliest value of a date or datetime-local property.
+func MinDate(value time.Time) DateBoundSetting {
+	return func(property *Property) {
+		property.Min = dateBound(property.Type, value)
+	}
+}
+
+// MaxDate sets the latest value of a date or datetime-local property.
+func MaxDate(value time.Time) DateBoundSetting {
+	return func(property *Property) {
+		property.Max = dateBound(property.Type, value)
+	}
+}
+
+// Selected sets the values of the options that are initially chosen.
+func Selected(values ...string) SelectionSetting {
+	return func(property *Property) {
+		switch options := property.Options.(type) {
+		case InlineOption:
+			options.SelectedValues = values
+			property.Options = options
+		case LinkOption:
+			options.SelectedValues = values
+			property.Options = options
+		}
+	}
+}
+
+// MinItems sets the minimum number of options that must be chosen.
+func MinItems(count uint32) ItemsSetting {
+	return func(property *Property) {
+		setItems(property, &count, nil)
+	}
+}
+
+// MaxItems sets the maximum number of options that may be chosen.
+func MaxItems(count uint32) ItemsSetting {
+	return func(property *Property) {
+		setItems(property, nil, &count)
+	}
+}
+
+// TextProperty creates a property for entering a single line of text.
+//
+// Parameters:
+//
+//	name - The name of the property.
+//	settings - Any settings to apply to the property.
+//
+// Returns:
+//
+//	The configured Property.
+//
+// Example:
+//
+//	// Describe a required title of up to 100 characters.
+//	title := gohalforms.TextProperty("title", gohalforms.Required(), gohalforms.Prompt("Title"), gohalforms.MaxLength(100))
+func TextProperty(name string, settings ...TextSetting) Property {
+	return textProperty(name, InputText, settings)
+}
+
+// EmailProperty creates a property for entering an email address.
+//
+// Parameters:
+//
+//	name - The name of the property.
+//	settings - Any settings to apply to the property.
+//
+// Returns:
+//
+//	The configured Property.
+func EmailProperty(name string, settings ...TextSetting) Property {
+	return textProperty(name, InputEmail, settings)
+}
+
+// PasswordProperty creates a property for entering a password.
+//
+// Parameters:
+//
+//	name - The name of the property.
+//	settings - Any settings to apply to the property.
+//
+// Returns:
+//
+//	The configured Property.
+func PasswordProperty(name string, settings ...TextSetting) Property {
+	return textProperty(name, InputPassword, settings)
+}
+
+// TelProperty creates a property for entering a telephone number.
+//
+// Parameters:
+//
+//	name - The name of the property.
+//	settings - Any settings to apply to the property.
+//
+// Returns:
+//
+//	The configured Property.
+func TelProperty(name string, settings ...TextSetting) Property {
+	return textProperty(name, InputTel, settings)
+}
+
+// URLProperty creates a property for entering a URL.
+//
+// Parameters:
+//
+//	name - The name of the property.
+//	settings - Any settings to apply to the property.
+//
+// Returns:
+//
+//	The configured Property.
+func URLProperty(name string, settings ...TextSetting) Property {
+	return textProperty(name, InputURL, settings)
+}
+
+// HiddenProperty creates a property whose value is submitted without being shown to the user.
+//
+// Parameters:
+//
+//	name - The name of the property.
+//	value - The value to submit.
+//
+// Returns:
+//
+//	The configured Property.
+func HiddenProperty(name string, value string) Property {
+	return Property{Name: name, Type: InputHidden, Value: value}
+}
+
+// TextAreaProperty creates a property for entering multiple lines of text.
+//
+// Parameters:
+//
+//	name - The name of the property.
+//	settings - Any settings to apply to the property.
+//
+// Returns:
+//
+//	The configured Property.
+//
+// Example:
+//
+//	// Describe a description field that is five lines high.
+//	description := gohalforms.TextAreaProperty("description", gohalforms.Rows(5), gohalforms.MaxLength(1000))
+func TextAreaProperty(name string, settings ...TextAreaSetting) Property {
+	property := Property{Name: name, Type: InputTextArea}
+	for _, setting := range settings {
+		setting.applyTextArea(&property)
+	}
+
+	return property
+}
+
+// NumberProperty creates a property for entering a number.
+//
+// Parameters:
+//
+//	name - The name of the property.
+//	settings - Any settings to apply to the property.
+//
+// Returns:
+//
+//	The configured Property.
+//
+// Example:
+//
+//	// Describe a price in pounds and pence.
+//	price := gohalforms.NumberProperty("price", gohalforms.Min(0), gohalforms.Step(0.01))
+func NumberProperty(name string, settings ...NumberSetting) Property {
+	return numberProperty(name, InputNumber, settings)
+}
+
+// RangeProperty creates a property for choosing a number from a range, typically using a slider.
+//
+// Parameters:
+//
+//	name - The name of the property.
+//	settings - Any settings to apply to the property.
+//
+// Returns:
+//
+//	The configured Property.
+func RangeProperty(name string, settings ...NumberSetting) Property {
+	return numberProperty(name, InputRange, settings)
+}
+
+// DateProperty creates a property for entering a date.
+//
+// Parameters:
+//
+//	name - The name of the property.
+//	settings - Any settings to apply to the property.
+//
+// Returns:
+//
+//	The configured Property.
+//
+// Example:
+//
+//	// Describe a date of birth in the past.
+//	birthday := gohalforms.DateProperty("birthday", gohalforms.MaxDate(time.Now()))
+func DateProperty(name string, settings ...DateSetting) Property {
+	return dateProperty(name, InputDate, settings)
+}
+
+// DateTimeProperty creates a property for entering a local date and time.
+//
+// Parameters:
+//
+//	name - The name of the property.
+//	settings - Any settings to apply to the property.
+//
+// Returns:
+//
+//	The configured Property.
+func DateTimeProperty(name string, settings ...DateSetting) Property {
+	return dateProperty(name, InputDateTimeLocal, settings)
+}
+
+// CheckboxProperty creates a property for a value that is either on or off.
+//
+// Parameters:
+//
+//	name - The name of the property.
+//	settings - Any settings to apply to the property.
+//
+// Returns:
+//
+//	The configured Property.
+func CheckboxProperty(name string, settings ...CheckboxSetting) Property {
+	property := Property{Name: name, Type: InputCheckbox}
+	for _, setting := range settings {
+		setting.applyCheckbox(&property)
+	}
+
+	return property
+}
+
+// SelectProperty creates a property for choosing exactly one of a set of options. A required select property must
+// have one option chosen.
+//
+// Parameters:
+//
+//	name - The name of the property.
+//	options - The options to choose from, either an InlineOption or a LinkOption.
+//	settings - Any settings to apply to the property.
+//
+// Returns:
+//
+//	The configured Property.
+//
+// Example:
+//
+//	// Describe a choice of colour.
+//	colour := gohalforms.SelectProperty("colour", gohalforms.InlineOption{
+//	    Inline: []gohalforms.InlineOptionValue{
+//	        {Prompt: "Red", Value: "red"},
+//	        {Prompt: "Blue", Value: "blue"},
+//	    },
+//	}, gohalforms.Selected("red"))
+func SelectProperty(name string, options PropertyOption, settings ...SelectSetting) Property {
+	property := Property{Name: name, Options: options}
+	one := uint32(1)
+	setItems(&property, nil, &one)
+
+	for _, setting := range settings {
+		setting.applySelect(&property)
+	}
+
+	if property.Required {
+		setItems(&property, &one, nil)
+	}
+
+	return property
+}
+
+// MultiSelectProperty creates a property for choosing any number of a set of options.
+//
+// Parameters:
+//
+//	name - The name of the property.
+//	options - The options to choose from, either an InlineOption or a LinkOption.
+//	settings - Any settings to apply to the property.
+//
+// Returns:
+//
+//	The configured Property.
+//
+// Example:
+//
+//	// Describe a choice of up to three tags, loaded from another resource.
+//	tags := gohalforms.MultiSelectProperty("tags", gohalforms.LinkOption{
+//	    Link: gohalforms.Link{Href: "/tags"},
+//	}, gohalforms.MaxItems(3))
+func MultiSelectProperty(name string, options PropertyOption, settings ...MultiSelectSetting) Property {
+	property := Property{Name: name, Options: options}
+	for _, setting := range settings {
+		setting.applyMultiSelect(&property)
+	}
+
+	return property
+}
+
+// textProperty creates a single line text property of the given type.
+func textProperty(name string, inputType InputType, settings []TextSetting) Property {
+	property := Property{Name: name, Type: inputType}
+	for _, setting := range settings {
+		setting.applyText(&property)
+	}
+
+	return property
+}
+
+// numberProperty creates a numeric property of the given type.
+func numberProperty(name string, inputType InputType, settings []NumberSetting) Property {
+	property := Property{Name: name, Type: inputType}
+	for _, setting := range settings {
+		setting.applyNumber(&property)
+	}
+
+	return property
+}
+
+// dateProperty creates a date property of the given type.
+func dateProperty(name string, inputType InputType, settings []DateSetting) Property {
+	property := Property{Name: name, Type: inputType}
+	for _, setting := range settings {
+		setting.applyDate(&property)
+	}
+
+	return property
+}
+
+// dateBound creates a bound suitable for a date property of the given type.
+func dateBound(inputType InputType, value time.Time) *Bound {
+	if inputType == InputDateTimeLocal {
+		return DateTimeBound(value)
+	}
+
+	return DateBound(value)
+}
+
+// setItems updates the minimum and maximum number of items that can be chosen from the options of a property.
+func setItems(property *Property, minItems *uint32, maxItems *uint32) {
+	update := func(currentMin *uint32, currentMax *uint32) {
+		if minItems != nil {
+			*currentMin = *minItems
+		}
+
+		if maxItems != nil {
+			*currentMax = *maxItems
+		}
+	}
+
+	switch options := property.Options.(type) {
+	case InlineOption:
+		update(&options.MinItems, &options.MaxItems)
+		property.Options = options
+	case LinkOption:
+		update(&options.MinItems, &options.MaxItems)
+		property.Options = options
+	}
+}
@@ -0,0 +1,136 @@
+package gohalforms_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sazzer/gohalforms"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextPropertyBuilders(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, gohalforms.Property{
+		Name:        "title",
+		Type:        gohalforms.InputText,
+		Required:    true,
+		Prompt:      "Title",
+		Placeholder: "Enter a title",
+		MinLength:   1,
+		MaxLength:   100,
+		Regex:       "[a-z]+",
+		Value:       "hello",
+		Readonly:    true,
+	}, gohalforms.TextProperty("title",
+		gohalforms.Required(),
+		gohalforms.Prompt("Title"),
+		gohalforms.Placeholder("Enter a title"),
+		gohalforms.MinLength(1),
+		gohalforms.MaxLength(100),
+		gohalforms.Pattern("[a-z]+"),
+		gohalforms.DefaultValue("hello"),
+		gohalforms.ReadOnly(),
+	))
+
+	assert.Equal(t, gohalforms.Property{Name: "email", Type: gohalforms.InputEmail}, gohalforms.EmailProperty("email"))
+	assert.Equal(t, gohalforms.Property{Name: "password", Type: gohalforms.InputPassword}, gohalforms.PasswordProperty("password"))
+	assert.Equal(t, gohalforms.Property{Name: "phone", Type: gohalforms.InputTel}, gohalforms.TelProperty("phone"))
+	assert.Equal(t, gohalforms.Property{Name: "homepage", Type: gohalforms.InputURL}, gohalforms.URLProperty("homepage"))
+	assert.Equal(t, gohalforms.Property{Name: "id", Type: gohalforms.InputHidden, Value: "1"}, gohalforms.HiddenProperty("id", "1"))
+
+	assert.Equal(t, gohalforms.Property{
+		Name:      "description",
+		Type:      gohalforms.InputTextArea,
+		Rows:      5,
+		Cols:      80,
+		MaxLength: 1000,
+	}, gohalforms.TextAreaProperty("description", gohalforms.Rows(5), gohalforms.Cols(80), gohalforms.MaxLength(1000)))
+}
+
+func TestNumberPropertyBuilders(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, gohalforms.Property{
+		Name:        "price",
+		Type:        gohalforms.InputNumber,
+		Min:         gohalforms.NumberBound(-1.5),
+		Max:         gohalforms.NumberBound(100),
+		Step:        0.5,
+		Placeholder: "0.00",
+	}, gohalforms.NumberProperty("price",
+		gohalforms.Min(-1.5),
+		gohalforms.Max(100),
+		gohalforms.Step(0.5),
+		gohalforms.Placeholder("0.00"),
+	))
+
+	assert.Equal(t, gohalforms.Property{
+		Name: "volume",
+		Type: gohalforms.InputRange,
+		Max:  gohalforms.NumberBound(11),
+	}, gohalforms.RangeProperty("volume", gohalforms.Max(11)))
+}
+
+func TestDatePropertyBuilders(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2020, 1, 1, 9, 30, 0, 0, time.UTC)
+	end := time.Date(2020, 12, 31, 17, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, gohalforms.Property{
+		Name:     "birthday",
+		Type:     gohalforms.InputDate,
+		Min:      gohalforms.DateBound(start),
+		Max:      gohalforms.DateBound(end),
+		Required: true,
+	}, gohalforms.DateProperty("birthday", gohalforms.MinDate(start), gohalforms.MaxDate(end), gohalforms.Required()))
+
+	assert.Equal(t, gohalforms.Property{
+		Name: "meeting",
+		Type: gohalforms.InputDateTimeLocal,
+		Min:  gohalforms.DateTimeBound(start),
+	}, gohalforms.DateTimeProperty("meeting", gohalforms.MinDate(start)))
+
+	assert.Equal(t, gohalforms.Property{
+		Name:  "agreed",
+		Type:  gohalforms.InputCheckbox,
+		Value: "true",
+	}, gohalforms.CheckboxProperty("agreed", gohalforms.DefaultValue("true")))
+}
+
+func TestSelectPropertyBuilders(t *testing.T) {
+	t.Parallel()
+
+	inline := gohalforms.InlineOption{
+		Inline: []gohalforms.InlineOptionValue{
+			{Prompt: "Red", Value: "red"},
+			{Prompt: "Blue", Value: "blue"},
+		},
+	}
+
+	assert.Equal(t, gohalforms.Property{
+		Name:     "colour",
+		Required: true,
+		Options: gohalforms.InlineOption{
+			Inline:         inline.Inline,
+			MinItems:       1,
+			MaxItems:       1,
+			SelectedValues: []string{"red"},
+		},
+	}, gohalforms.SelectProperty("colour", inline, gohalforms.Required(), gohalforms.Selected("red")))
+
+	assert.Equal(t, gohalforms.Property{
+		Name: "tags",
+		Options: gohalforms.LinkOption{
+			Link:           gohalforms.Link{Href: "/tags"},
+			MinItems:       1,
+			MaxItems:       3,
+			SelectedValues: []string{"a", "b"},
+		},
+	}, gohalforms.MultiSelectProperty("tags", gohalforms.LinkOption{Link: gohalforms.Link{Href: "/tags"}},
+		gohalforms.MinItems(1),
+		gohalforms.MaxItems(3),
+		gohalforms.Selected("a", "b"),
+	))
+}
@@ -0,0 +1,143 @@
+package gohalforms
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnknownRoute is returned when a link is built for a route that has not been registered.
+	ErrUnknownRoute = errors.New("unknown route")
+	// ErrMissingParameter is returned when a link is built without a parameter that its route requires.
+	ErrMissingParameter = errors.New("missing parameter")
+)
+
+// MissingParameterError describes a parameter that a route requires, but that was not provided when building a link.
+type MissingParameterError struct {
+	// Route is the name of the route.
+	Route string
+	// Parameter is the name of the missing parameter.
+	Parameter string
+}
+
+// Error returns a description of the missing parameter.
+func (err MissingParameterError) Error() string {
+	return fmt.Sprintf("route %q requires parameter %q", err.Route, err.Parameter)
+}
+
+// Unwrap allows a MissingParameterError to be matched against ErrMissingParameter.
+func (err MissingParameterError) Unwrap() error {
+	return ErrMissingParameter
+}
+
+// Registry holds named routes, each declared once with a URI template, so that the hrefs of links are built from the
+// same routes that the server handles instead of being hard-coded. URI templates follow RFC 6570, including its
+// operators and the explode and prefix modifiers, with slices expanded as lists and maps as associative arrays. Simple
+// {name}, reserved {+name} and path segment {/name} expressions are required, and all other expressions are optional.
+type Registry struct {
+	routes map[string]string
+}
+
+// NewRegistry creates a new Registry with no routes.
+//
+// Returns:
+//
+//	A pointer to the new Registry.
+//
+// Example:
+//
+//	// Declare the routes of the service.
+//	routes := gohalforms.NewRegistry().
+//	    Register("users", "/users{?page,size}").
+//	    Register("user", "/users/{id}")
+func NewRegistry() *Registry {
+	return &Registry{
+		routes: map[string]string{},
+	}
+}
+
+// Register declares a named route, replacing any existing route with the same name.
+//
+// Parameters:
+//
+//	registry - A pointer to the Registry to which the route should be added.
+//	name - The name of the route.
+//	uriTemplate - The URI template of the route.
+//
+// Returns:
+//
+//	The same Registry, so that calls can be chained.
+func (registry *Registry) Register(name string, uriTemplate string) *Registry {
+	registry.routes[name] = uriTemplate
+
+	return registry
+}
+
+// Link builds a link to a named route, expanding its URI template with the provided parameters. Every required
+// expression must have a parameter, and optional expressions without a parameter are left out of the href.
+//
+// Parameters:
+//
+//	name - The name of the route.
+//	params - The parameters used to expand the URI template.
+//
+// Returns:
+//
+//	The Link to the route, or an error if the route is unknown or a MissingParameterError if a required parameter is missing.
+//
+// Example:
+//
+//	// Link to a single user.
+//	self, err := routes.Link("user", map[string]any{"id": 5})
+//	if err != nil {
+//	    // Handle the error.
+//	}
+//
+//	halResource.AddLink("self", self)
+func (registry *Registry) Link(name string, params map[string]any) (Link, error) {
+	uriTemplate, ok := registry.routes[name]
+	if !ok {
+		return Link{}, fmt.Errorf("%w: %s", ErrUnknownRoute, name)
+	}
+
+	href, missing, _ := expandURI(uriTemplate, params, false)
+	if len(missing) > 0 {
+		return Link{}, MissingParameterError{Route: name, Parameter: missing[0]}
+	}
+
+	return Link{Href: href}, nil
+}
+
+// TemplatedLink builds a link to a named route, expanding its URI template with the provided parameters and leaving
+// the expressions for any other parameters in place. If any expressions remain, the link is marked as templated so that
+// clients can fill them in.
+//
+// Parameters:
+//
+//	name - The name of the route.
+//	params - The parameters used to expand the URI template, which may be nil.
+//
+// Returns:
+//
+//	The Link to the route, or an error if the route is unknown.
+//
+// Example:
+//
+//	// Link to the list of users, letting the client choose the page.
+//	users, err := routes.TemplatedLink("users", nil)
+//	if err != nil {
+//	    // Handle the error.
+//	}
+//
+//	// Adds {"href": "/users{?page,size}", "templated": true}
+//	halResource.AddLink("users", users)
+func (registry *Registry) TemplatedLink(name string, params map[string]any) (Link, error) {
+	uriTemplate, ok := registry.routes[name]
+	if !ok {
+		return Link{}, fmt.Errorf("%w: %s", ErrUnknownRoute, name)
+	}
+
+	href, _, templated := expandURI(uriTemplate, params, true)
+
+	return Link{Href: href, Templated: templated}, nil
+}
@@ -0,0 +1,201 @@
+package gohalforms_test
+
+import (
+	"testing"
+
+	"github.com/sazzer/gohalforms"
+	"github.com/stretchr/testify/assert"
+)
+
+func routes() *gohalforms.Registry {
+	return gohalforms.NewRegistry().
+		Register("users", "/users{?page,size}").
+		Register("user", "/users/{id}").
+		Register("posts", "/users/{id}/posts?sort=date{&page}").
+		Register("search", "/search{?q}").
+		Register("file", "/files{/path*}{.format}").
+		Register("docs", "{+base}/docs{#section}").
+		Register("items", "/items{?tags,filter*}")
+}
+
+func TestRegistryLink(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		route    string
+		params   map[string]any
+		expected gohalforms.Link
+	}{
+		{
+			name:     "Path parameter",
+			route:    "user",
+			params:   map[string]any{"id": 5},
+			expected: gohalforms.Link{Href: "/users/5"},
+		},
+		{
+			name:     "Escaped path parameter",
+			route:    "user",
+			params:   map[string]any{"id": "a/b c"},
+			expected: gohalforms.Link{Href: "/users/a%2Fb%20c"},
+		},
+		{
+			name:     "All query parameters",
+			route:    "users",
+			params:   map[string]any{"page": 2, "size": 10},
+			expected: gohalforms.Link{Href: "/users?page=2&size=10"},
+		},
+		{
+			name:     "Some query parameters",
+			route:    "users",
+			params:   map[string]any{"size": 10},
+			expected: gohalforms.Link{Href: "/users?size=10"},
+		},
+		{
+			name:     "No query parameters",
+			route:    "users",
+			params:   nil,
+			expected: gohalforms.Link{Href: "/users"},
+		},
+		{
+			name:     "Query continuation",
+			route:    "posts",
+			params:   map[string]any{"id": 5, "page": 3},
+			expected: gohalforms.Link{Href: "/users/5/posts?sort=date&page=3"},
+		},
+		{
+			name:     "Path segments",
+			route:    "file",
+			params:   map[string]any{"path": []string{"a", "b c"}, "format": "json"},
+			expected: gohalforms.Link{Href: "/files/a/b%20c.json"},
+		},
+		{
+			name:     "Optional label",
+			route:    "file",
+			params:   map[string]any{"path": "a"},
+			expected: gohalforms.Link{Href: "/files/a"},
+		},
+		{
+			name:     "Reserved expansion and fragment",
+			route:    "docs",
+			params:   map[string]any{"base": "https://example.com/api", "section": "a b/c"},
+			expected: gohalforms.Link{Href: "https://example.com/api/docs#a%20b/c"},
+		},
+		{
+			name:     "Query list and exploded map",
+			route:    "items",
+			params:   map[string]any{"tags": []string{"red", "blue"}, "filter": map[string]any{"size": "L", "colour": "red"}},
+			expected: gohalforms.Link{Href: "/items?tags=red,blue&colour=red&size=L"},
+		},
+		{
+			name:     "Empty list",
+			route:    "items",
+			params:   map[string]any{"tags": []string{}},
+			expected: gohalforms.Link{Href: "/items"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			link, err := routes().Link(test.route, test.params)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, link)
+		})
+	}
+}
+
+func TestRegistryLinkMissingParameter(t *testing.T) {
+	t.Parallel()
+
+	_, err := routes().Link("user", map[string]any{"name": "Graham"})
+	assert.ErrorIs(t, err, gohalforms.ErrMissingParameter)
+	assert.EqualError(t, err, `route "user" requires parameter "id"`)
+
+	var missing gohalforms.MissingParameterError
+	assert.ErrorAs(t, err, &missing)
+	assert.Equal(t, gohalforms.MissingParameterError{Route: "user", Parameter: "id"}, missing)
+
+	_, err = routes().Link("docs", map[string]any{"section": "intro"})
+	assert.Equal(t, gohalforms.MissingParameterError{Route: "docs", Parameter: "base"}, err)
+
+	_, err = routes().Link("file", map[string]any{"format": "json"})
+	assert.Equal(t, gohalforms.MissingParameterError{Route: "file", Parameter: "path"}, err)
+}
+
+func TestRegistryUnknownRoute(t *testing.T) {
+	t.Parallel()
+
+	_, err := routes().Link("unknown", nil)
+	assert.ErrorIs(t, err, gohalforms.ErrUnknownRoute)
+
+	_, err = routes().TemplatedLink("unknown", nil)
+	assert.ErrorIs(t, err, gohalforms.ErrUnknownRoute)
+}
+
+func TestRegistryTemplatedLink(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		route    string
+		params   map[string]any
+		expected gohalforms.Link
+	}{
+		{
+			name:     "Nothing expanded",
+			route:    "users",
+			expected: gohalforms.Link{Href: "/users{?page,size}", Templated: true},
+		},
+		{
+			name:     "Partially expanded",
+			route:    "users",
+			params:   map[string]any{"page": 1},
+			expected: gohalforms.Link{Href: "/users?page=1{&size}", Templated: true},
+		},
+		{
+			name:     "Path expanded",
+			route:    "posts",
+			params:   map[string]any{"id": 5},
+			expected: gohalforms.Link{Href: "/users/5/posts?sort=date{&page}", Templated: true},
+		},
+		{
+			name:     "Fully expanded",
+			route:    "user",
+			params:   map[string]any{"id": 5},
+			expected: gohalforms.Link{Href: "/users/5"},
+		},
+		{
+			name:     "Missing path parameter",
+			route:    "user",
+			expected: gohalforms.Link{Href: "/users/{id}", Templated: true},
+		},
+		{
+			name:     "Missing path segments",
+			route:    "file",
+			params:   map[string]any{"format": "json"},
+			expected: gohalforms.Link{Href: "/files{/path*}.json", Templated: true},
+		},
+		{
+			name:     "Missing fragment",
+			route:    "docs",
+			params:   map[string]any{"base": "/api"},
+			expected: gohalforms.Link{Href: "/api/docs{#section}", Templated: true},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			link, err := routes().TemplatedLink(test.route, test.params)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, link)
+		})
+	}
+}
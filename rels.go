@@ -0,0 +1,444 @@
+package gohalforms
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// The link relations registered with IANA, as listed at https://www.iana.org/assignments/link-relations.
+const (
+	RelAbout                  = "about"
+	RelACL                    = "acl"
+	RelAlternate              = "alternate"
+	RelAMPHTML                = "amphtml"
+	RelAppendix               = "appendix"
+	RelAppleTouchIcon         = "apple-touch-icon"
+	RelAppleTouchStartupImage = "apple-touch-startup-image"
+	RelArchives               = "archives"
+	RelAuthor                 = "author"
+	RelBlockedBy              = "blocked-by"
+	RelBookmark               = "bookmark"
+	RelCanonical              = "canonical"
+	RelChapter                = "chapter"
+	RelCiteAs                 = "cite-as"
+	RelCollection             = "collection"
+	RelContents               = "contents"
+	RelConvertedFrom          = "convertedfrom"
+	RelCopyright              = "copyright"
+	RelCreateForm             = "create-form"
+	RelCurrent                = "current"
+	RelDeprecation            = "deprecation"
+	RelDescribedBy            = "describedby"
+	RelDescribes              = "describes"
+	RelDisclosure             = "disclosure"
+	RelDNSPrefetch            = "dns-prefetch"
+	RelDuplicate              = "duplicate"
+	RelEdit                   = "edit"
+	RelEditForm               = "edit-form"
+	RelEditMedia              = "edit-media"
+	RelEnclosure              = "enclosure"
+	RelExternal               = "external"
+	RelFirst                  = "first"
+	RelGlossary               = "glossary"
+	RelHelp                   = "help"
+	RelHosts                  = "hosts"
+	RelHub                    = "hub"
+	RelIcon                   = "icon"
+	RelIndex                  = "index"
+	RelIntervalAfter          = "intervalafter"
+	RelIntervalBefore         = "intervalbefore"
+	RelIntervalContains       = "intervalcontains"
+	RelIntervalDisjoint       = "intervaldisjoint"
+	RelIntervalDuring         = "intervalduring"
+	RelIntervalEquals         = "intervalequals"
+	RelIntervalFinishedBy     = "intervalfinishedby"
+	RelIntervalFinishes       = "intervalfinishes"
+	RelIntervalIn             = "intervalin"
+	RelIntervalMeets          = "intervalmeets"
+	RelIntervalMetBy          = "intervalmetby"
+	RelIntervalOverlappedBy   = "intervaloverlappedby"
+	RelIntervalOverlaps       = "intervaloverlaps"
+	RelIntervalStartedBy      = "intervalstartedby"
+	RelIntervalStarts         = "intervalstarts"
+	RelItem                   = "item"
+	RelLast                   = "last"
+	RelLatestVersion          = "latest-version"
+	RelLicense                = "license"
+	RelLinkset                = "linkset"
+	RelLRDD                   = "lrdd"
+	RelManifest               = "manifest"
+	RelMaskIcon               = "mask-icon"
+	RelMe                     = "me"
+	RelMediaFeed              = "media-feed"
+	RelMemento                = "memento"
+	RelMicropub               = "micropub"
+	RelModulePreload          = "modulepreload"
+	RelMonitor                = "monitor"
+	RelMonitorGroup           = "monitor-group"
+	RelNext                   = "next"
+	RelNextArchive            = "next-archive"
+	RelNoFollow               = "nofollow"
+	RelNoOpener               = "noopener"
+	RelNoReferrer             = "noreferrer"
+	RelOpener                 = "opener"
+	RelOpenID2LocalID         = "openid2.local_id"
+	RelOpenID2Provider        = "openid2.provider"
+	RelOriginal               = "original"
+	RelP3Pv1                  = "p3pv1"
+	RelPayment                = "payment"
+	RelPingback               = "pingback"
+	RelPreconnect             = "preconnect"
+	RelPredecessorVersion     = "predecessor-version"
+	RelPrefetch               = "prefetch"
+	RelPreload                = "preload"
+	RelPrerender              = "prerender"
+	RelPrev                   = "prev"
+	RelPrevArchive            = "prev-archive"
+	RelPreview                = "preview"
+	RelPrevious               = "previous"
+	RelPrivacyPolicy          = "privacy-policy"
+	RelProfile                = "profile"
+	RelPublication            = "publication"
+	RelRelated                = "related"
+	RelReplies                = "replies"
+	RelRESTCONF               = "restconf"
+	RelRuleInput              = "ruleinput"
+	RelSearch                 = "search"
+	RelSection                = "section"
+	RelSelf                   = "self"
+	RelService                = "service"
+	RelServiceDesc            = "service-desc"
+	RelServiceDoc             = "service-doc"
+	RelServiceMeta            = "service-meta"
+	RelSponsored              = "sponsored"
+	RelStart                  = "start"
+	RelStatus                 = "status"
+	RelStylesheet             = "stylesheet"
+	RelSubsection             = "subsection"
+	RelSuccessorVersion       = "successor-version"
+	RelSunset                 = "sunset"
+	RelTag                    = "tag"
+	RelTermsOfService         = "terms-of-service"
+	RelTimeGate               = "timegate"
+	RelTimeMap                = "timemap"
+	RelType                   = "type"
+	RelUGC                    = "ugc"
+	RelUp                     = "up"
+	RelVersionHistory         = "version-history"
+	RelVia                    = "via"
+	RelWebmention             = "webmention"
+	RelWorkingCopy            = "working-copy"
+	RelWorkingCopyOf          = "working-copy-of"
+)
+
+// RelCuries is the relation used by HAL for the links that define CURIE prefixes. It is not registered with IANA, but
+// is always valid within a HAL resource.
+const RelCuries = "curies"
+
+// registeredRels are the link relations registered with IANA.
+var registeredRels = map[string]bool{
+	RelAbout:                  true,
+	RelACL:                    true,
+	RelAlternate:              true,
+	RelAMPHTML:                true,
+	RelAppendix:               true,
+	RelAppleTouchIcon:         true,
+	RelAppleTouchStartupImage: true,
+	RelArchives:               true,
+	RelAuthor:                 true,
+	RelBlockedBy:              true,
+	RelBookmark:               true,
+	RelCanonical:              true,
+	RelChapter:                true,
+	RelCiteAs:                 true,
+	RelCollection:             true,
+	RelContents:               true,
+	RelConvertedFrom:          true,
+	RelCopyright:              true,
+	RelCreateForm:             true,
+	RelCurrent:                true,
+	RelDeprecation:            true,
+	RelDescribedBy:            true,
+	RelDescribes:              true,
+	RelDisclosure:             true,
+	RelDNSPrefetch:            true,
+	RelDuplicate:              true,
+	RelEdit:                   true,
+	RelEditForm:               true,
+	RelEditMedia:              true,
+	RelEnclosure:              true,
+	RelExternal:               true,
+	RelFirst:                  true,
+	RelGlossary:               true,
+	RelHelp:                   true,
+	RelHosts:                  true,
+	RelHub:                    true,
+	RelIcon:                   true,
+	RelIndex:                  true,
+	RelIntervalAfter:          true,
+	RelIntervalBefore:         true,
+	RelIntervalContains:       true,
+	RelIntervalDisjoint:       true,
+	RelIntervalDuring:         true,
+	RelIntervalEquals:         true,
+	RelIntervalFinishedBy:     true,
+	RelIntervalFinishes:       true,
+	RelIntervalIn:             true,
+	RelIntervalMeets:          true,
+	RelIntervalMetBy:          true,
+	RelIntervalOverlappedBy:   true,
+	RelIntervalOverlaps:       true,
+	RelIntervalStartedBy:      true,
+	RelIntervalStarts:         true,
+	RelItem:                   true,
+	RelLast:                   true,
+	RelLatestVersion:          true,
+	RelLicense:                true,
+	RelLinkset:                true,
+	RelLRDD:                   true,
+	RelManifest:               true,
+	RelMaskIcon:               true,
+	RelMe:                     true,
+	RelMediaFeed:              true,
+	RelMemento:                true,
+	RelMicropub:               true,
+	RelModulePreload:          true,
+	RelMonitor:                true,
+	RelMonitorGroup:           true,
+	RelNext:                   true,
+	RelNextArchive:            true,
+	RelNoFollow:               true,
+	RelNoOpener:               true,
+	RelNoReferrer:             true,
+	RelOpener:                 true,
+	RelOpenID2LocalID:         true,
+	RelOpenID2Provider:        true,
+	RelOriginal:               true,
+	RelP3Pv1:                  true,
+	RelPayment:                true,
+	RelPingback:               true,
+	RelPreconnect:             true,
+	RelPredecessorVersion:     true,
+	RelPrefetch:               true,
+	RelPreload:                true,
+	RelPrerender:              true,
+	RelPrev:                   true,
+	RelPrevArchive:            true,
+	RelPreview:                true,
+	RelPrevious:               true,
+	RelPrivacyPolicy:          true,
+	RelProfile:                true,
+	RelPublication:            true,
+	RelRelated:                true,
+	RelReplies:                true,
+	RelRESTCONF:               true,
+	RelRuleInput:              true,
+	RelSearch:                 true,
+	RelSection:                true,
+	RelSelf:                   true,
+	RelService:                true,
+	RelServiceDesc:            true,
+	RelServiceDoc:             true,
+	RelServiceMeta:            true,
+	RelSponsored:              true,
+	RelStart:                  true,
+	RelStatus:                 true,
+	RelStylesheet:             true,
+	RelSubsection:             true,
+	RelSuccessorVersion:       true,
+	RelSunset:                 true,
+	RelTag:                    true,
+	RelTermsOfService:         true,
+	RelTimeGate:               true,
+	RelTimeMap:                true,
+	RelType:                   true,
+	RelUGC:                    true,
+	RelUp:                     true,
+	RelVersionHistory:         true,
+	RelVia:                    true,
+	RelWebmention:             true,
+	RelWorkingCopy:            true,
+	RelWorkingCopyOf:          true,
+}
+
+// RelKind classifies a link relation according to how it is defined.
+type RelKind int
+
+const (
+	// RelKindUnknown is a relation that is neither registered, nor a CURIE, nor an absolute URI.
+	RelKindUnknown RelKind = iota
+	// RelKindRegistered is a relation registered with IANA, or the HAL "curies" relation.
+	RelKindRegistered
+	// RelKindCURIE is a compact URI, such as "acme:widgets", whose prefix is expanded using the "curies" links.
+	RelKindCURIE
+	// RelKindExtension is an extension relation written as an absolute URI, such as "https://example.com/rels/widgets".
+	RelKindExtension
+)
+
+// String returns the name of the kind of relation.
+func (kind RelKind) String() string {
+	switch kind {
+	case RelKindRegistered:
+		return "registered"
+	case RelKindCURIE:
+		return "CURIE"
+	case RelKindExtension:
+		return "extension"
+	default:
+		return "unknown"
+	}
+}
+
+// curiePattern matches a compact URI, optionally in the bracketed safe CURIE form.
+var curiePattern = regexp.MustCompile(`^(\[)?([A-Za-z_][A-Za-z0-9_.-]*):([^\]]*)(?:\])?$`)
+
+// ClassifyRel determines how a link relation is defined. Registered relations are matched case-insensitively.
+// Relations containing "://", or using the urn or tag schemes, are extension relations. Any other relation of the form
+// "prefix:reference" is a CURIE.
+//
+// Parameters:
+//
+//	rel - The link relation to classify.
+//
+// Returns:
+//
+//	The kind of the relation.
+//
+// Example:
+//
+//	gohalforms.ClassifyRel("edit-form")                        // RelKindRegistered
+//	gohalforms.ClassifyRel("acme:widgets")                     // RelKindCURIE
+//	gohalforms.ClassifyRel("https://example.com/rels/widgets") // RelKindExtension
+//	gohalforms.ClassifyRel("widgets")                          // RelKindUnknown
+func ClassifyRel(rel string) RelKind {
+	if registeredRels[strings.ToLower(rel)] || rel == RelCuries {
+		return RelKindRegistered
+	}
+
+	if parsed, err := url.Parse(rel); err == nil && parsed.Scheme != "" {
+		scheme := strings.ToLower(parsed.Scheme)
+		if strings.Contains(rel, "://") || scheme == "urn" || scheme == "tag" {
+			return RelKindExtension
+		}
+	}
+
+	if curiePrefix(rel) != "" {
+		return RelKindCURIE
+	}
+
+	return RelKindUnknown
+}
+
+// curiePrefix returns the prefix of a CURIE relation, or an empty string if the relation is not a CURIE.
+func curiePrefix(rel string) string {
+	match := curiePattern.FindStringSubmatch(rel)
+	if match == nil || (match[1] == "[") != strings.HasSuffix(rel, "]") {
+		return ""
+	}
+
+	return match[2]
+}
+
+// ErrUnknownRel is returned when strict relations are enforced and a resource uses a relation that is not allowed.
+var ErrUnknownRel = errors.New("unknown link relation")
+
+// RelError describes a relation that is not allowed when strict relations are enforced.
+type RelError struct {
+	// Rel is the relation that is not allowed.
+	Rel string
+	// Location is a JSON Pointer to where the relation is used.
+	Location string
+}
+
+// Error returns a description of the relation that is not allowed.
+func (err RelError) Error() string {
+	return fmt.Sprintf("link relation %q at %s is not registered, an absolute URI or a known CURIE", err.Rel, err.Location)
+}
+
+// Unwrap allows a RelError to be matched against ErrUnknownRel.
+func (err RelError) Unwrap() error {
+	return ErrUnknownRel
+}
+
+// WithStrictRels configures encoding to fail with a RelError if any link or embedded resource uses a relation that is
+// not registered with IANA, not an absolute URI, and not a CURIE whose prefix is defined by a "curies" link on the
+// resource or any resource that it is embedded in. This catches misspelt relations such as "edit_form".
+//
+// Returns:
+//
+//	An EncodeOption enforcing the relations.
+//
+// Example:
+//
+//	// Fail if any relation is misspelt.
+//	encoded, err := gohalforms.Marshal(halResource, gohalforms.WithStrictRels())
+//	if errors.Is(err, gohalforms.ErrUnknownRel) {
+//	    // Handle the invalid relation.
+//	}
+func WithStrictRels() EncodeOption {
+	return func(options *encodeOptions) {
+		options.strictRels = true
+	}
+}
+
+// checkRels ensures that every relation used by a resource is allowed, when strict relations are enforced. CURIE
+// prefixes defined by the resource are added to those in scope, and the returned function removes them again once the
+// resource has been written.
+func (state *encodeState) checkRels(resource Resource, location string) (func(), error) {
+	if !state.options.strictRels {
+		return func() {}, nil
+	}
+
+	scope := len(state.curies)
+
+	for _, curie := range resource.links.get(RelCuries) {
+		if curie.Name != "" {
+			state.curies = append(state.curies, curie.Name)
+		}
+	}
+
+	release := func() {
+		state.curies = state.curies[:scope]
+	}
+
+	for _, rel := range resource.links.keys(false) {
+		if !state.allowedRel(rel) {
+			release()
+
+			return nil, RelError{Rel: rel, Location: location + "/_links/" + escapePointer(rel)}
+		}
+	}
+
+	for _, rel := range resource.embedded.keys(false) {
+		if !state.allowedRel(rel) {
+			release()
+
+			return nil, RelError{Rel: rel, Location: location + "/_embedded/" + escapePointer(rel)}
+		}
+	}
+
+	return release, nil
+}
+
+// allowedRel determines whether a relation is allowed, given the CURIE prefixes that are currently in scope.
+func (state *encodeState) allowedRel(rel string) bool {
+	switch ClassifyRel(rel) {
+	case RelKindRegistered, RelKindExtension:
+		return true
+	case RelKindCURIE:
+		prefix := curiePrefix(rel)
+
+		for _, name := range state.curies {
+			if name == prefix {
+				return true
+			}
+		}
+
+		return false
+	default:
+		return false
+	}
+}
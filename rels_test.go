@@ -0,0 +1,96 @@
+package gohalforms_test
+
+import (
+	"testing"
+
+	"github.com/sazzer/gohalforms"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyRel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		rel      string
+		expected gohalforms.RelKind
+	}{
+		{rel: gohalforms.RelSelf, expected: gohalforms.RelKindRegistered},
+		{rel: gohalforms.RelEditForm, expected: gohalforms.RelKindRegistered},
+		{rel: "Collection", expected: gohalforms.RelKindRegistered},
+		{rel: gohalforms.RelCuries, expected: gohalforms.RelKindRegistered},
+		{rel: "acme:widgets", expected: gohalforms.RelKindCURIE},
+		{rel: "[acme:widgets]", expected: gohalforms.RelKindCURIE},
+		{rel: "https://example.com/rels/widgets", expected: gohalforms.RelKindExtension},
+		{rel: "urn:example:widgets", expected: gohalforms.RelKindExtension},
+		{rel: "widgets", expected: gohalforms.RelKindUnknown},
+		{rel: "edit_form", expected: gohalforms.RelKindUnknown},
+		{rel: "[acme:widgets", expected: gohalforms.RelKindUnknown},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.rel, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, gohalforms.ClassifyRel(test.rel))
+		})
+	}
+}
+
+func TestRelKindString(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "registered", gohalforms.RelKindRegistered.String())
+	assert.Equal(t, "CURIE", gohalforms.RelKindCURIE.String())
+	assert.Equal(t, "extension", gohalforms.RelKindExtension.String())
+	assert.Equal(t, "unknown", gohalforms.RelKindUnknown.String())
+}
+
+func TestMarshalStrictRels(t *testing.T) {
+	t.Parallel()
+
+	item := gohalforms.NewResource(nil)
+	item.AddLink(gohalforms.RelSelf, gohalforms.Link{Href: "/widgets/1"})
+	item.AddLink("acme:parts", gohalforms.Link{Href: "/widgets/1/parts"})
+
+	resource := gohalforms.NewResource(nil)
+	resource.AddLink(gohalforms.RelCuries, gohalforms.Link{Href: "/docs/{rel}", Name: "acme", Templated: true})
+	resource.AddLink(gohalforms.RelCollection, gohalforms.Link{Href: "/widgets"})
+	resource.AddLink("https://example.com/rels/owner", gohalforms.Link{Href: "/owners/1"})
+	resource.AddEmbedded("acme:widgets", item)
+
+	_, err := gohalforms.Marshal(resource, gohalforms.WithStrictRels())
+	assert.NoError(t, err)
+
+	_, err = gohalforms.Marshal(item, gohalforms.WithStrictRels())
+	assert.ErrorIs(t, err, gohalforms.ErrUnknownRel)
+	assert.EqualError(t, err, `link relation "acme:parts" at /_links/acme:parts is not registered, an absolute URI or a known CURIE`)
+
+	// Without strict relations, anything goes.
+	_, err = gohalforms.Marshal(item)
+	assert.NoError(t, err)
+}
+
+func TestMarshalStrictRelsUnknown(t *testing.T) {
+	t.Parallel()
+
+	item := gohalforms.NewResource(nil)
+	item.AddLink("edit_form", gohalforms.Link{Href: "/widgets/1/edit"})
+
+	resource := gohalforms.NewResource(nil)
+	resource.AddEmbedded("items", item)
+
+	_, err := gohalforms.Marshal(resource, gohalforms.WithStrictRels())
+
+	var relError gohalforms.RelError
+	assert.ErrorAs(t, err, &relError)
+	assert.Equal(t, gohalforms.RelError{Rel: "items", Location: "/_embedded/items"}, relError)
+
+	resource = gohalforms.NewResource(nil)
+	resource.AddEmbedded(gohalforms.RelItem, item)
+
+	_, err = gohalforms.Marshal(resource, gohalforms.WithStrictRels())
+	assert.ErrorAs(t, err, &relError)
+	assert.Equal(t, gohalforms.RelError{Rel: "edit_form", Location: "/_embedded/item/_links/edit_form"}, relError)
+}
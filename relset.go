@@ -0,0 +1,82 @@
+package gohalforms
+
+import "sort"
+
+// relset is an ordered map where the keys represent relation names, remembering the order in which each relation was first added.
+type relset[V any] struct {
+	rels   []string
+	values map[string]V
+}
+
+// newRelset creates a new, empty relset.
+func newRelset[V any]() *relset[V] {
+	return &relset[V]{
+		rels:   []string{},
+		values: map[string]V{},
+	}
+}
+
+// len returns the number of relations in the relset.
+func (set *relset[V]) len() int {
+	if set == nil {
+		return 0
+	}
+
+	return len(set.rels)
+}
+
+// get returns the value stored under a relation, or the zero value if there is none.
+func (set *relset[V]) get(rel string) V {
+	if set == nil {
+		var zero V
+
+		return zero
+	}
+
+	return set.values[rel]
+}
+
+// has determines whether a value is stored under a relation.
+func (set *relset[V]) has(rel string) bool {
+	if set == nil {
+		return false
+	}
+
+	_, exists := set.values[rel]
+
+	return exists
+}
+
+// set stores a value under a relation, replacing any existing value without changing the position of the relation.
+func (set *relset[V]) set(rel string, value V) {
+	if _, exists := set.values[rel]; !exists {
+		set.rels = append(set.rels, rel)
+	}
+
+	set.values[rel] = value
+}
+
+// keys returns the relations in the relset, either in the order in which they were first added or sorted alphabetically.
+func (set *relset[V]) keys(sorted bool) []string {
+	if set == nil {
+		return nil
+	}
+
+	keys := append([]string{}, set.rels...)
+	if sorted {
+		sort.Strings(keys)
+	}
+
+	return keys
+}
+
+// clone returns a shallow copy of the relset, which can be modified without affecting the original.
+func (set *relset[V]) clone() *relset[V] {
+	result := newRelset[V]()
+
+	for _, rel := range set.keys(false) {
+		result.set(rel, set.values[rel])
+	}
+
+	return result
+}
@@ -1,13 +1,15 @@
 package gohalforms
 
-import "encoding/json"
-
 // Resource represents a generic representation of a HAL (Hypertext Application Language) resource.
 type Resource struct {
 	payload   any
-	links     linkset
-	embedded  resourceset
-	templates map[string]Template
+	links     *linkset
+	embedded  *resourceset
+	templates *relset[Template]
+	// deprecation, if set, marks the resource itself as deprecated.
+	deprecation *Deprecation
+	// identity, if set, is the type and id of the resource, for formats that identify resources.
+	identity *identity
 }
 
 // New creates a new instance of the Resource type with the provided payload.
@@ -31,12 +33,38 @@ type Resource struct {
 func NewResource(payload any) Resource {
 	return Resource{
 		payload:   payload,
-		links:     linkset{},
-		embedded:  resourceset{},
-		templates: map[string]Template{},
+		links:     newRelset[links](),
+		embedded:  newRelset[resources](),
+		templates: newRelset[Template](),
 	}
 }
 
+// NewResourceChecked creates a new instance of the Resource type with the provided payload, checking up front that the
+// payload can be encoded as a JSON object. NewResource defers this check until the resource is encoded.
+//
+// Parameters:
+//
+//	payload - The payload associated with the HAL resource.
+//
+// Returns:
+//
+//	A Resource instance containing the specified payload, or a PayloadNotObjectError if the payload does not encode to a JSON object.
+//
+// Example:
+//
+//	// Fail early if the payload is not suitable for a HAL resource.
+//	halResource, err := gohalforms.NewResourceChecked(payload)
+//	if errors.Is(err, gohalforms.ErrPayloadNotObject) {
+//	    // Handle the invalid payload.
+//	}
+func NewResourceChecked(payload any) (Resource, error) {
+	if _, err := encodePayload(payload, "", ""); err != nil {
+		return Resource{}, err
+	}
+
+	return NewResource(payload), nil
+}
+
 // AddLink adds a new hyperlink to the HAL (Hypertext Application Language) resource under the specified relation.
 //
 // Parameters:
@@ -61,7 +89,7 @@ func NewResource(payload any) Resource {
 //	// Add the link to the HAL resource under the "related" relation.
 //	halResource.AddLink("related", newLink)
 func (resource *Resource) AddLink(rel string, value Link) {
-	resource.links[rel] = append(resource.links[rel], value)
+	resource.links.set(rel, append(resource.links.get(rel), value))
 }
 
 // AddEmbedded adds a new embedded HAL resource to the HAL (Hypertext Application Language) resource under the specified relation.
@@ -87,10 +115,48 @@ func (resource *Resource) AddLink(rel string, value Link) {
 //	// Add the embedded resource to the HAL resource under the "items" relation.
 //	halResource.AddEmbedded("items", embeddedResource)
 func (resource *Resource) AddEmbedded(rel string, value Resource) {
-	resource.embedded[rel] = append(resource.embedded[rel], value)
+	embedded := resource.embedded.get(rel)
+	embedded.items = append(embedded.items, value)
+	resource.embedded.set(rel, embedded)
+}
+
+// AddEmbeddedSeq adds a stream of embedded HAL resources to the HAL (Hypertext Application Language) resource under the specified relation.
+// The stream is not consumed until the resource is encoded, so the embedded resources are never all held in memory at once.
+// Because of this, the stream is consumed every time the resource is encoded and must only be encoded once unless it can be replayed.
+// Embedded resources added from a stream are always encoded as an array, after any that were added individually with AddEmbedded.
+// Encoding may stop before the stream is finished, or without starting it at all, for example if a limit is exceeded. Streams
+// fed by another goroutine should tie that goroutine to a context that is cancelled once the response is complete.
+//
+// Parameters:
+//
+//	resource - A pointer to the Resource instance to which the embedded resources should be added.
+//	rel - The relation name under which the embedded resources will be stored.
+//	seq - An iterator producing the embedded resources. This has the same shape as iter.Seq[Resource].
+//
+// Example:
+//
+//	// Stream every row in the database as an embedded resource.
+//	halResource.AddEmbeddedSeq("items", func(yield func(gohalforms.Resource) bool) {
+//	    for rows.Next() {
+//	        var row Row
+//	        if err := rows.Scan(&row.ID, &row.Name); err != nil {
+//	            return
+//	        }
+//
+//	        if !yield(gohalforms.NewResource(row)) {
+//	            return
+//	        }
+//	    }
+//	})
+func (resource *Resource) AddEmbeddedSeq(rel string, seq func(yield func(Resource) bool)) {
+	embedded := resource.embedded.get(rel)
+	embedded.streams = append(embedded.streams, seq)
+	resource.embedded.set(rel, embedded)
 }
 
 // AddTemplate adds a new template to the HAL (Hypertext Application Language) resource under the specified relation.
+// If the resource has only a single template, it is encoded under the key "default" whatever relation it was added
+// under, as required by the HAL-FORMS specification.
 //
 // Parameters:
 //
@@ -117,37 +183,15 @@ func (resource *Resource) AddEmbedded(rel string, value Resource) {
 //	// Add the template to the HAL resource under the "create" relation.
 //	halResource.AddTemplate("create", newTemplate)
 func (resource *Resource) AddTemplate(rel string, value Template) {
-	resource.templates[rel] = value
+	resource.templates.set(rel, value)
 }
 
+// MarshalJSON serializes the HAL resource to JSON, combining the payload with the links, embedded resources and templates.
+//
+// Returns:
+//
+//	A JSON representation of the resource, or an error if the payload could not be represented as a JSON object or
+//	contains members that collide with the HAL members of the resource.
 func (resource Resource) MarshalJSON() ([]byte, error) {
-	intermediate := map[string]any{}
-
-	if resource.payload != nil {
-		// Marshal the payload portion to JSON.
-		raw, err := json.Marshal(resource.payload)
-		if err != nil {
-			return nil, err
-		}
-
-		// Unmarshal this into a Map.
-		if err = json.Unmarshal(raw, &intermediate); err != nil {
-			return nil, err
-		}
-	}
-
-	if len(resource.links) > 0 {
-		intermediate["_links"] = resource.links
-	}
-
-	if len(resource.embedded) > 0 {
-		intermediate["_embedded"] = resource.embedded
-	}
-
-	if len(resource.templates) > 0 {
-		intermediate["_templates"] = resource.templates
-	}
-
-	// Re-marshal this to JSON.
-	return json.Marshal(intermediate)
+	return Marshal(resource)
 }
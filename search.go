@@ -0,0 +1,312 @@
+package gohalforms
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrNotSearchTemplate is returned when a query string is parsed against a template that is not submitted with GET.
+var ErrNotSearchTemplate = errors.New("template is not a search template")
+
+// FieldError describes a single property of a template that failed validation.
+type FieldError struct {
+	Name    string
+	Message string
+}
+
+// Error returns a description of the validation failure.
+func (err FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", err.Name, err.Message)
+}
+
+// FieldErrors is a collection of validation failures for the properties of a template.
+type FieldErrors []FieldError
+
+// Error returns a description of all the validation failures.
+func (errs FieldErrors) Error() string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// SearchValues holds the filter values parsed from a query string, keyed by property name.
+// Properties that accept multiple values are represented as a []any.
+type SearchValues map[string]any
+
+// NewSearchTemplate creates a HAL-FORMS template describing a search form whose properties are submitted as query string parameters.
+//
+// Parameters:
+//
+//	target - The URL that the search form is submitted to.
+//	title - The human-readable title of the search form.
+//	filters - The properties describing each filter parameter that the search accepts.
+//
+// Returns:
+//
+//	A Template that submits the filters to the target using GET.
+//
+// Example:
+//
+//	// Describe a search over users.
+//	search := gohalforms.NewSearchTemplate("/users", "Search users",
+//	    gohalforms.Property{Name: "name", Prompt: "Name"},
+//	    gohalforms.Property{Name: "age", Type: gohalforms.InputNumber, Min: gohalforms.NumberBound(18)},
+//	)
+//
+//	// Advertise it on the collection resource.
+//	halResource.AddTemplate("search", search)
+func NewSearchTemplate(target string, title string, filters ...Property) Template {
+	return Template{
+		ContentType: "application/x-www-form-urlencoded",
+		Method:      http.MethodGet,
+		Target:      target,
+		Title:       title,
+		Properties:  filters,
+	}
+}
+
+// ParseQuery parses the query string of an incoming HTTP request against the properties of a search template,
+// converting each value according to the property type and validating it against the property constraints.
+// Query parameters that do not correspond to a property are ignored.
+//
+// Parameters:
+//
+//	r - The incoming HTTP request.
+//
+// Returns:
+//
+//	The parsed filter values, or an error if the template is not a search template or any filters were invalid.
+//	Validation failures are reported as FieldErrors.
+//
+// Example:
+//
+//	// Parse the filters that a client submitted using the same template that was advertised.
+//	filters, err := search.ParseQuery(r)
+//	if err != nil {
+//	    // Respond with a 400 Bad Request.
+//	}
+func (template Template) ParseQuery(r *http.Request) (SearchValues, error) {
+	if template.Method != "" && !strings.EqualFold(template.Method, http.MethodGet) {
+		return nil, ErrNotSearchTemplate
+	}
+
+	query := r.URL.Query()
+	values := SearchValues{}
+
+	var errs FieldErrors
+
+	for _, property := range template.Properties {
+		value, err := property.parseQuery(query)
+
+		var fieldError FieldError
+
+		switch {
+		case errors.As(err, &fieldError):
+			errs = append(errs, fieldError)
+		case err != nil:
+			return nil, err
+		case value != nil:
+			values[property.Name] = value
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return values, nil
+}
+
+// parseQuery extracts the value of a single property from a query string.
+func (property Property) parseQuery(query url.Values) (any, error) {
+	raw := []string{}
+
+	for _, value := range query[property.Name] {
+		if value != "" {
+			raw = append(raw, value)
+		}
+	}
+
+	if len(raw) == 0 {
+		if property.Required {
+			return nil, FieldError{Name: property.Name, Message: "is required"}
+		}
+
+		return nil, nil
+	}
+
+	multiple := property.allowsMultiple()
+	if !multiple && len(raw) > 1 {
+		return nil, FieldError{Name: property.Name, Message: "expects a single value"}
+	}
+
+	if err := property.validateCount(len(raw)); err != nil {
+		return nil, err
+	}
+
+	converted := make([]any, 0, len(raw))
+
+	for _, value := range raw {
+		result, err := property.convert(value)
+		if err != nil {
+			return nil, err
+		}
+
+		converted = append(converted, result)
+	}
+
+	if multiple {
+		return converted, nil
+	}
+
+	return converted[0], nil
+}
+
+// allowsMultiple determines whether a property accepts more than one value.
+func (property Property) allowsMultiple() bool {
+	switch options := property.Options.(type) {
+	case InlineOption:
+		return options.MaxItems != 1
+	case LinkOption:
+		return options.MaxItems != 1
+	default:
+		return false
+	}
+}
+
+// validateCount checks the number of values supplied for a property against its option constraints.
+func (property Property) validateCount(count int) error {
+	var minItems, maxItems uint32
+
+	switch options := property.Options.(type) {
+	case InlineOption:
+		minItems, maxItems = options.MinItems, options.MaxItems
+	case LinkOption:
+		minItems, maxItems = options.MinItems, options.MaxItems
+	}
+
+	if minItems > 0 && count < int(minItems) {
+		return FieldError{Name: property.Name, Message: fmt.Sprintf("expects at least %d values", minItems)}
+	}
+
+	if maxItems > 0 && count > int(maxItems) {
+		return FieldError{Name: property.Name, Message: fmt.Sprintf("expects at most %d values", maxItems)}
+	}
+
+	return nil
+}
+
+// convert validates a single raw value for a property and converts it to the Go type matching the property type.
+func (property Property) convert(value string) (any, error) {
+	fail := func(message string) error {
+		return FieldError{Name: property.Name, Message: message}
+	}
+
+	if property.MinLength > 0 && utf8.RuneCountInString(value) < int(property.MinLength) {
+		return nil, fail(fmt.Sprintf("must be at least %d characters", property.MinLength))
+	}
+
+	if property.MaxLength > 0 && utf8.RuneCountInString(value) > int(property.MaxLength) {
+		return nil, fail(fmt.Sprintf("must be at most %d characters", property.MaxLength))
+	}
+
+	if property.Regex != "" {
+		pattern, err := regexp.Compile("^(?:" + property.Regex + ")$")
+		if err != nil {
+			return nil, err
+		}
+
+		if !pattern.MatchString(value) {
+			return nil, fail("does not match the required pattern")
+		}
+	}
+
+	if options, ok := property.Options.(InlineOption); ok && !options.contains(value) {
+		return nil, fail(fmt.Sprintf("%q is not an allowed value", value))
+	}
+
+	switch property.Type {
+	case InputNumber, InputRange:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fail("must be a number")
+		}
+
+		if limit, ok := property.Min.numberValue(); ok && number < limit {
+			return nil, fail(fmt.Sprintf("must be at least %s", property.Min))
+		}
+
+		if limit, ok := property.Max.numberValue(); ok && number > limit {
+			return nil, fail(fmt.Sprintf("must be at most %s", property.Max))
+		}
+
+		return number, nil
+	case InputCheckbox:
+		if value == "on" {
+			return true, nil
+		}
+
+		checked, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fail("must be a boolean")
+		}
+
+		return checked, nil
+	case InputDate, InputDateTimeLocal, InputTime:
+		layouts := []string{property.Type.layout()}
+		if property.Type != InputDate {
+			layouts = append(layouts, layouts[0]+":05")
+		}
+
+		parsed, err := parseTime(value, fail, layouts...)
+		if err != nil {
+			return nil, err
+		}
+
+		if limit, ok := property.Min.timeValue(); ok && parsed.Before(limit) {
+			return nil, fail(fmt.Sprintf("must not be before %s", property.Min))
+		}
+
+		if limit, ok := property.Max.timeValue(); ok && parsed.After(limit) {
+			return nil, fail(fmt.Sprintf("must not be after %s", property.Max))
+		}
+
+		if property.Type == InputTime {
+			return value, nil
+		}
+
+		return parsed, nil
+	case InputEmail:
+		if _, err := mail.ParseAddress(value); err != nil {
+			return nil, fail("must be an email address")
+		}
+	case InputURL:
+		if parsed, err := url.Parse(value); err != nil || !parsed.IsAbs() {
+			return nil, fail("must be an absolute URL")
+		}
+	}
+
+	return value, nil
+}
+
+// parseTime parses a value using the first of the provided layouts that matches.
+func parseTime(value string, fail func(string) error, layouts ...string) (time.Time, error) {
+	for _, layout := range layouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, nil
+		}
+	}
+
+	return time.Time{}, fail(fmt.Sprintf("must be in the format %s", layouts[0]))
+}
@@ -0,0 +1,141 @@
+package gohalforms_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kinbiko/jsonassert"
+	"github.com/sazzer/gohalforms"
+	"github.com/stretchr/testify/assert"
+)
+
+func searchTemplate() gohalforms.Template {
+	return gohalforms.NewSearchTemplate("/users", "Search users",
+		gohalforms.Property{Name: "name", MinLength: 2},
+		gohalforms.Property{Name: "age", Type: gohalforms.InputNumber, Min: gohalforms.NumberBound(18), Max: gohalforms.NumberBound(99)},
+		gohalforms.Property{Name: "active", Type: gohalforms.InputCheckbox},
+		gohalforms.Property{Name: "joined", Type: gohalforms.InputDate, Min: gohalforms.DateBound(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))},
+		gohalforms.Property{Name: "code", Regex: "[A-Z]{3}"},
+		gohalforms.Property{
+			Name:     "status",
+			Required: true,
+			Options: gohalforms.InlineOption{
+				Inline: []gohalforms.InlineOptionValue{
+					{Prompt: "Active", Value: "active"},
+					{Prompt: "Blocked", Value: "blocked"},
+				},
+			},
+		},
+	)
+}
+
+func TestMarshalSearchTemplate(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(nil)
+	resource.AddTemplate("default", gohalforms.NewSearchTemplate("/users", "Search users",
+		gohalforms.Property{Name: "name", Prompt: "Name"},
+	))
+
+	encoded, err := json.Marshal(resource)
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"_templates" : {
+			"default" : {
+				"title" : "Search users",
+				"method" : "GET",
+				"target" : "/users",
+				"contentType" : "application/x-www-form-urlencoded",
+				"properties" : [
+					{"name" : "name", "prompt" : "Name"}
+				]
+			}
+		}
+	}`)
+}
+
+func TestParseQueryValid(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodGet,
+		"/users?name=Graham&age=42&active=on&joined=2023-10-01&code=ABC&status=active&status=blocked&page=3", nil)
+
+	values, err := searchTemplate().ParseQuery(r)
+	assert.NoError(t, err)
+	assert.Equal(t, gohalforms.SearchValues{
+		"name":   "Graham",
+		"age":    42.0,
+		"active": true,
+		"joined": time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+		"code":   "ABC",
+		"status": []any{"active", "blocked"},
+	}, values)
+}
+
+func TestParseQueryMinimal(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodGet, "/users?status=active&name=", nil)
+
+	values, err := searchTemplate().ParseQuery(r)
+	assert.NoError(t, err)
+	assert.Equal(t, gohalforms.SearchValues{
+		"status": []any{"active"},
+	}, values)
+}
+
+func TestParseQueryInvalid(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodGet,
+		"/users?name=G&age=12&active=maybe&joined=yesterday&code=abcd&age=13", nil)
+
+	_, err := searchTemplate().ParseQuery(r)
+
+	var errs gohalforms.FieldErrors
+	assert.ErrorAs(t, err, &errs)
+	assert.Equal(t, gohalforms.FieldErrors{
+		{Name: "name", Message: "must be at least 2 characters"},
+		{Name: "age", Message: "expects a single value"},
+		{Name: "active", Message: "must be a boolean"},
+		{Name: "joined", Message: "must be in the format 2006-01-02"},
+		{Name: "code", Message: "does not match the required pattern"},
+		{Name: "status", Message: "is required"},
+	}, errs)
+}
+
+func TestParseQueryNotSearch(t *testing.T) {
+	t.Parallel()
+
+	template := gohalforms.Template{Method: http.MethodPost}
+
+	_, err := template.ParseQuery(httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.ErrorIs(t, err, gohalforms.ErrNotSearchTemplate)
+}
+
+func TestParseQueryTypedInlineOptions(t *testing.T) {
+	t.Parallel()
+
+	template := gohalforms.NewSearchTemplate("/users", "Search users",
+		gohalforms.Property{
+			Name: "rating",
+			Type: gohalforms.InputNumber,
+			Options: gohalforms.InlineOption{
+				Inline:   []gohalforms.InlineOptionValue{{Prompt: "One", Value: 1}, {Prompt: "Two", Value: 2}},
+				MaxItems: 1,
+			},
+		},
+	)
+
+	values, err := template.ParseQuery(httptest.NewRequest(http.MethodGet, "/users?rating=2", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, gohalforms.SearchValues{"rating": 2.0}, values)
+
+	_, err = template.ParseQuery(httptest.NewRequest(http.MethodGet, "/users?rating=3", nil))
+	assert.Error(t, err)
+}
@@ -0,0 +1,181 @@
+package gohalforms
+
+import "encoding/json"
+
+// SirenContentType is the media type of resources encoded as Siren.
+const SirenContentType = "application/vnd.siren+json"
+
+// sirenEntity is a Siren entity, or a sub-entity when it has a relation.
+type sirenEntity struct {
+	Rel        []string        `json:"rel,omitempty"`
+	Properties json.RawMessage `json:"properties,omitempty"`
+	Entities   []sirenEntity   `json:"entities,omitempty"`
+	Actions    []sirenAction   `json:"actions,omitempty"`
+	Links      []sirenLink     `json:"links,omitempty"`
+}
+
+// sirenLink is a Siren link, which may have several relations.
+type sirenLink struct {
+	Rel   []string `json:"rel"`
+	Href  string   `json:"href"`
+	Title string   `json:"title,omitempty"`
+	Type  string   `json:"type,omitempty"`
+}
+
+// sirenAction is a Siren action, describing how to submit a request.
+type sirenAction struct {
+	Name   string       `json:"name"`
+	Title  string       `json:"title,omitempty"`
+	Method string       `json:"method"`
+	Href   string       `json:"href"`
+	Type   string       `json:"type,omitempty"`
+	Fields []sirenField `json:"fields,omitempty"`
+}
+
+// sirenField is a single field of a Siren action.
+type sirenField struct {
+	Name  string `json:"name"`
+	Type  string `json:"type,omitempty"`
+	Value string `json:"value,omitempty"`
+	Title string `json:"title,omitempty"`
+}
+
+// MarshalSiren encodes a HAL (Hypertext Application Language) resource as a Siren entity, so that the same resource
+// can be served to clients that speak Siren. The payload becomes the "properties" of the entity, links become "links",
+// embedded resources become sub-entities, and templates become "actions" whose fields are mapped from the template
+// properties. Links to the same target under different relations are combined into a single link.
+//
+// Siren has no equivalent of templated links or property options, so these are left out.
+//
+// Parameters:
+//
+//	resource - The Resource instance to encode.
+//	options - Any options to control the encoding.
+//
+// Returns:
+//
+//	The Siren representation of the resource, or an error if it could not be encoded.
+//
+// Example:
+//
+//	// Encode a HAL resource for a Siren client.
+//	encoded, err := gohalforms.MarshalSiren(halResource)
+func MarshalSiren(resource Resource, options ...EncodeOption) ([]byte, error) {
+	resource, settings, err := limitedResource(resource, options)
+	if err != nil {
+		return nil, err
+	}
+
+	entity, err := sirenEntityOf(resource, settings, "")
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	return settings.limits.checkSize(encoded)
+}
+
+// sirenEntityOf converts a resource found at the given location into a Siren entity.
+func sirenEntityOf(resource Resource, settings encodeOptions, location string) (sirenEntity, error) {
+	entity := sirenEntity{}
+
+	payload, err := encodePayload(resource.payload, settings.wrapPayload, location)
+	if err != nil {
+		return sirenEntity{}, err
+	}
+
+	if len(payload) > 0 {
+		entity.Properties = append(append([]byte{'{'}, payload...), '}')
+	}
+
+	for _, rel := range resource.embedded.keys(false) {
+		values := resource.embedded.get(rel)
+
+		for index, item := range values.all() {
+			sub, err := sirenEntityOf(item, settings, values.location(location+"/_embedded/"+escapePointer(rel), index))
+			if err != nil {
+				return sirenEntity{}, err
+			}
+
+			sub.Rel = []string{rel}
+			entity.Entities = append(entity.Entities, sub)
+		}
+	}
+
+	for _, name := range resource.templates.keys(false) {
+		entity.Actions = append(entity.Actions, resource.sirenAction(name, resource.templates.get(name)))
+	}
+
+	entity.Links = sirenLinks(resource.links)
+
+	return entity, nil
+}
+
+// sirenLinks converts the links of a resource into Siren links, combining links that differ only by relation.
+func sirenLinks(values *linkset) []sirenLink {
+	type identity struct {
+		href, title, mediaType string
+	}
+
+	var result []sirenLink
+
+	positions := map[identity]int{}
+
+	for _, rel := range values.keys(false) {
+		for _, link := range values.get(rel) {
+			if link.Templated {
+				continue
+			}
+
+			key := identity{href: link.Href, title: link.Title, mediaType: link.Type}
+
+			if position, exists := positions[key]; exists {
+				result[position].Rel = append(result[position].Rel, rel)
+
+				continue
+			}
+
+			positions[key] = len(result)
+			result = append(result, sirenLink{Rel: []string{rel}, Href: link.Href, Title: link.Title, Type: link.Type})
+		}
+	}
+
+	return result
+}
+
+// sirenAction converts a template into a Siren action.
+func (resource Resource) sirenAction(name string, template Template) sirenAction {
+	action := sirenAction{
+		Name:   name,
+		Title:  template.Title,
+		Method: template.effectiveMethod(),
+		Href:   resource.effectiveTarget(template),
+		Type:   template.ContentType,
+	}
+
+	if action.Type == "" {
+		action.Type = "application/json"
+	}
+
+	for _, property := range template.Properties {
+		field := sirenField{
+			Name:  property.Name,
+			Type:  string(property.Type),
+			Value: property.Value,
+			Title: property.Prompt,
+		}
+
+		// Siren fields follow the HTML input types, which have no textarea.
+		if property.Type == InputTextArea {
+			field.Type = string(InputText)
+		}
+
+		action.Fields = append(action.Fields, field)
+	}
+
+	return action
+}
@@ -0,0 +1,150 @@
+package gohalforms_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/kinbiko/jsonassert"
+	"github.com/sazzer/gohalforms"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalSiren(t *testing.T) {
+	t.Parallel()
+
+	item := gohalforms.NewResource(map[string]any{"id": 1})
+	item.AddLink("self", gohalforms.Link{Href: "/orders/1/items/1"})
+
+	resource := gohalforms.NewResource(map[string]any{"total": 42})
+	resource.AddLink("self", gohalforms.Link{Href: "/orders/1"})
+	resource.AddLink("canonical", gohalforms.Link{Href: "/orders/1"})
+	resource.AddLink("customer", gohalforms.Link{Href: "/customers/1", Title: "Customer", Type: "application/json"})
+	resource.AddLink("search", gohalforms.Link{Href: "/orders{?q}", Templated: true})
+	resource.AddEmbedded("item", item)
+	resource.AddTemplate("update", gohalforms.Template{
+		Method: http.MethodPut,
+		Title:  "Update order",
+		Properties: []gohalforms.Property{
+			gohalforms.NumberProperty("total", gohalforms.Prompt("Total"), gohalforms.DefaultValue("42")),
+			gohalforms.TextAreaProperty("notes"),
+		},
+	})
+	resource.AddTemplate("pay", gohalforms.Template{
+		Method:      http.MethodPost,
+		Target:      "/payments",
+		ContentType: "application/x-www-form-urlencoded",
+	})
+
+	encoded, err := gohalforms.MarshalSiren(resource)
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"properties": {"total": 42},
+		"entities": [
+			{
+				"rel": ["item"],
+				"properties": {"id": 1},
+				"links": [{"rel": ["self"], "href": "/orders/1/items/1"}]
+			}
+		],
+		"actions": [
+			{
+				"name": "update",
+				"title": "Update order",
+				"method": "PUT",
+				"href": "/orders/1",
+				"type": "application/json",
+				"fields": [
+					{"name": "total", "type": "number", "value": "42", "title": "Total"},
+					{"name": "notes", "type": "text"}
+				]
+			},
+			{
+				"name": "pay",
+				"method": "POST",
+				"href": "/payments",
+				"type": "application/x-www-form-urlencoded"
+			}
+		],
+		"links": [
+			{"rel": ["self", "canonical"], "href": "/orders/1"},
+			{"rel": ["customer"], "href": "/customers/1", "title": "Customer", "type": "application/json"}
+		]
+	}`)
+}
+
+func TestMarshalSirenStreamsAndWrapping(t *testing.T) {
+	t.Parallel()
+
+	var produced int
+
+	resource := gohalforms.NewResource([]string{"a", "b"})
+	resource.AddEmbeddedSeq("items", countTo(2, &produced))
+
+	_, err := gohalforms.MarshalSiren(resource)
+	assert.ErrorIs(t, err, gohalforms.ErrPayloadNotObject)
+
+	encoded, err := gohalforms.MarshalSiren(resource, gohalforms.WithPayloadWrapping("values"))
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"properties": {"values": ["a", "b"]},
+		"entities": [
+			{"rel": ["items"], "properties": {"index": 1}},
+			{"rel": ["items"], "properties": {"index": 2}}
+		]
+	}`)
+}
+
+func TestMarshalSirenLimits(t *testing.T) {
+	t.Parallel()
+
+	_, err := gohalforms.MarshalSiren(nestedResource(3), gohalforms.WithLimits(gohalforms.Limits{MaxDepth: 2}))
+	assert.Equal(t, gohalforms.LimitError{
+		Limit:    "depth",
+		Max:      2,
+		Location: "/_embedded/child/_embedded/child/_embedded/child",
+	}, err)
+
+	produced := 0
+
+	resource := gohalforms.NewResource(nil)
+	resource.AddEmbeddedSeq("items", countTo(100, &produced))
+
+	_, err = gohalforms.MarshalSiren(resource, gohalforms.WithLimits(gohalforms.Limits{MaxEmbedded: 2}))
+	assert.Equal(t, gohalforms.LimitError{Limit: "embedded", Max: 2, Location: "/_embedded/items"}, err)
+	assert.Equal(t, 3, produced)
+
+	encoded, err := gohalforms.MarshalSiren(nestedResource(1), gohalforms.WithLimits(gohalforms.Limits{MaxSize: 10}))
+	assert.Equal(t, gohalforms.LimitError{Limit: "size", Max: 10}, err)
+	assert.Nil(t, encoded)
+}
+
+func TestMarshalSirenTruncated(t *testing.T) {
+	t.Parallel()
+
+	produced := 0
+
+	resource := gohalforms.NewResource(nil)
+	resource.AddEmbeddedSeq("items", countTo(100, &produced))
+
+	encoded, err := gohalforms.MarshalSiren(resource, gohalforms.WithLimits(gohalforms.Limits{
+		MaxEmbedded: 2,
+		Truncate: func(rel string, kept int) gohalforms.Link {
+			return gohalforms.Link{Href: "/items?offset=2"}
+		},
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, produced)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"entities": [
+			{"rel": ["items"], "properties": {"index": 1}},
+			{"rel": ["items"], "properties": {"index": 2}}
+		],
+		"links": [{"rel": ["next"], "href": "/items?offset=2"}]
+	}`)
+}
@@ -1,5 +1,11 @@
 package gohalforms
 
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
 // Template represents a template for creating or updating a HAL (Hypertext Application Language) resource.
 type Template struct {
 	ContentType string     `json:"contentType,omitempty"`
@@ -7,6 +13,12 @@ type Template struct {
 	Target      string     `json:"target,omitempty"`
 	Title       string     `json:"title,omitempty"`
 	Properties  []Property `json:"properties"`
+	// Deprecation is the URL of documentation describing the deprecation of the template, mirroring the property of the same name on HAL links.
+	Deprecation string `json:"deprecation,omitempty"`
+	// DeprecationDate is when the template was deprecated, in RFC 3339 format. It is set by Deprecate.
+	DeprecationDate string `json:"deprecationDate,omitempty"`
+	// Sunset is when the template is expected to stop working, in RFC 3339 format. It is set by Deprecate.
+	Sunset string `json:"sunset,omitempty"`
 }
 
 // Property represents a property definition within a HAL resource template.
@@ -19,15 +31,15 @@ type Property struct {
 	Templated   bool           `json:"templated,omitempty"`
 	Value       string         `json:"value,omitempty"`
 	Cols        uint32         `json:"cols,omitempty"`
-	Max         uint32         `json:"max,omitempty"`
+	Max         *Bound         `json:"max,omitempty"`
 	MaxLength   uint32         `json:"maxLength,omitempty"`
-	Min         uint32         `json:"min,omitempty"`
+	Min         *Bound         `json:"min,omitempty"`
 	MinLength   uint32         `json:"minLength,omitempty"`
 	Options     PropertyOption `json:"options,omitempty"`
 	Placeholder string         `json:"placeholder,omitempty"`
 	Rows        uint32         `json:"rows,omitempty"`
-	Step        uint32         `json:"step,omitempty"`
-	Type        string         `json:"type,omitempty"`
+	Step        float64        `json:"step,omitempty"`
+	Type        InputType      `json:"type,omitempty"`
 }
 
 // PropertyOption is an interface for representing various property options in a HAL resource template.
@@ -35,26 +47,37 @@ type PropertyOption interface {
 	isAnOption()
 }
 
-// InlineOptionValue represents a single value within a set of inline options.
+// InlineOptionValue represents a single value within a set of inline options. The value may be a string, number or
+// boolean. Values with no prompt use the value itself as the prompt.
 type InlineOptionValue struct {
 	Prompt string `json:"prompt"`
-	Value  string `json:"value"`
+	Value  any    `json:"value"`
 }
 
 // InlineOption represents a property option for inline values within a HAL resource template.
+// When every value is a string with no prompt, and no fields are mapped, the values are encoded as a plain string array.
 type InlineOption struct {
-	Inline         []InlineOptionValue `json:"inline"`
-	MaxItems       uint32              `json:"maxItems,omitempty"`
-	MinItems       uint32              `json:"minItems,omitempty"`
-	SelectedValues []string            `json:"selectedValues,omitempty"`
+	Inline []InlineOptionValue
+	// PromptField is the name of the member of each encoded value holding its prompt. Defaults to "prompt".
+	PromptField string
+	// ValueField is the name of the member of each encoded value holding its value. Defaults to "value".
+	ValueField     string
+	MaxItems       uint32
+	MinItems       uint32
+	SelectedValues []string
 }
 
 // isAnOption is a method to indicate that InlineOption implements the PropertyOption interface.
 func (InlineOption) isAnOption() {}
 
 // LinkOption represents a property option for link values within a HAL resource template.
+// The link target returns the option values, which can be served using SendOptions.
 type LinkOption struct {
-	Link           Link     `json:"link"`
+	Link Link `json:"link"`
+	// PromptField is the name of the member of each option value holding its prompt. Defaults to "prompt".
+	PromptField string `json:"promptField,omitempty"`
+	// ValueField is the name of the member of each option value holding its value. Defaults to "value".
+	ValueField     string   `json:"valueField,omitempty"`
 	MaxItems       uint32   `json:"maxItems,omitempty"`
 	MinItems       uint32   `json:"minItems,omitempty"`
 	SelectedValues []string `json:"selectedValues,omitempty"`
@@ -62,3 +85,145 @@ type LinkOption struct {
 
 // isAnOption is a method to indicate that LinkOption implements the PropertyOption interface.
 func (LinkOption) isAnOption() {}
+
+// DefaultTemplateName is the key of the template that HAL-FORMS clients use by default.
+// When a resource has only a single template, the HAL-FORMS specification requires it to have this key.
+const DefaultTemplateName = "default"
+
+// SetDefaultTemplate sets the default template of the HAL (Hypertext Application Language) resource, which is the one
+// that HAL-FORMS clients use unless they are told to pick another.
+//
+// Parameters:
+//
+//	resource - A pointer to the Resource instance to which the template should be added.
+//	value - The Template instance to use as the default.
+//
+// Example:
+//
+//	// Describe how to update the resource.
+//	halResource.SetDefaultTemplate(gohalforms.Template{
+//	    Method:     http.MethodPut,
+//	    Properties: []gohalforms.Property{{Name: "title"}},
+//	})
+func (resource *Resource) SetDefaultTemplate(value Template) {
+	resource.AddTemplate(DefaultTemplateName, value)
+}
+
+// AddAlternateTemplate adds a named alternative to the default template of the HAL (Hypertext Application Language) resource.
+// If the resource ends up with only a single template, it is still encoded with the key "default" as the HAL-FORMS
+// specification requires.
+//
+// Parameters:
+//
+//	resource - A pointer to the Resource instance to which the template should be added.
+//	name - The key of the alternate template. This must not be "default".
+//	value - The Template instance to add.
+//
+// Returns:
+//
+//	An error if the name is "default"; otherwise, it returns nil.
+//
+// Example:
+//
+//	// Describe how to delete the resource, alongside the default update template.
+//	err := halResource.AddAlternateTemplate("delete", gohalforms.Template{Method: http.MethodDelete})
+func (resource *Resource) AddAlternateTemplate(name string, value Template) error {
+	if name == DefaultTemplateName {
+		return fmt.Errorf("alternate template must not be named %q", DefaultTemplateName)
+	}
+
+	resource.AddTemplate(name, value)
+
+	return nil
+}
+
+// DefaultTemplate returns the template that HAL-FORMS clients use by default. This is the template named "default",
+// or the only template if the resource has just one.
+//
+// Returns:
+//
+//	The default template, and true if there is one.
+func (resource Resource) DefaultTemplate() (Template, bool) {
+	templates := resource.encodedTemplates()
+	if templates.len() == 0 {
+		return Template{}, false
+	}
+
+	value, ok := templates.values[DefaultTemplateName]
+
+	return value, ok
+}
+
+// SelectTemplate determines which template a client should use to submit a request with the given method to the given target.
+// Following the HAL-FORMS specification, a template without a method uses GET and one without a target submits to the
+// href of the resource's "self" link. If more than one template matches, the default template is preferred, followed by
+// the first matching template that was added.
+//
+// Parameters:
+//
+//	method - The HTTP method of the request. This is matched case-insensitively.
+//	target - The URL that the request is submitted to.
+//
+// Returns:
+//
+//	The key and value of the matching template, and true if a template matched.
+//
+// Example:
+//
+//	// Find the template describing how to update the resource.
+//	name, template, ok := halResource.SelectTemplate(http.MethodPut, "/users/1")
+func (resource Resource) SelectTemplate(method string, target string) (string, Template, bool) {
+	templates := resource.encodedTemplates()
+	found := ""
+
+	for _, name := range templates.keys(false) {
+		value := templates.get(name)
+		if !strings.EqualFold(value.effectiveMethod(), method) || resource.effectiveTarget(value) != target {
+			continue
+		}
+
+		if found == "" || name == DefaultTemplateName {
+			found = name
+		}
+	}
+
+	if found == "" {
+		return "", Template{}, false
+	}
+
+	return found, templates.get(found), true
+}
+
+// effectiveMethod returns the HTTP method that the template is submitted with.
+func (template Template) effectiveMethod() string {
+	if template.Method == "" {
+		return http.MethodGet
+	}
+
+	return template.Method
+}
+
+// effectiveTarget returns the URL that a template of this resource is submitted to.
+func (resource Resource) effectiveTarget(template Template) string {
+	if template.Target != "" {
+		return template.Target
+	}
+
+	if self := resource.links.get(RelSelf); len(self) > 0 {
+		return self[0].Href
+	}
+
+	return ""
+}
+
+// encodedTemplates returns the templates of the resource as they are encoded, with a single template always named "default".
+func (resource Resource) encodedTemplates() *relset[Template] {
+	if resource.templates.len() != 1 || resource.templates.rels[0] == DefaultTemplateName {
+		return resource.templates
+	}
+
+	templates := newRelset[Template]()
+	templates.set(DefaultTemplateName, resource.templates.get(resource.templates.rels[0]))
+
+	return templates
+}
@@ -0,0 +1,103 @@
+package gohalforms_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/kinbiko/jsonassert"
+	"github.com/sazzer/gohalforms"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalSingleTemplateIsDefault(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(nil)
+	resource.AddTemplate("create", gohalforms.Template{Method: http.MethodPost})
+
+	encoded, err := json.Marshal(resource)
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"_templates" : {
+			"default" : {
+				"method" : "POST",
+				"properties" : null
+			}
+		}
+	}`)
+
+	template, ok := resource.DefaultTemplate()
+	assert.True(t, ok)
+	assert.Equal(t, gohalforms.Template{Method: http.MethodPost}, template)
+}
+
+func TestMarshalAlternateTemplates(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(nil)
+	resource.SetDefaultTemplate(gohalforms.Template{Method: http.MethodPut})
+	assert.NoError(t, resource.AddAlternateTemplate("delete", gohalforms.Template{Method: http.MethodDelete}))
+	assert.Error(t, resource.AddAlternateTemplate("default", gohalforms.Template{}))
+
+	encoded, err := json.Marshal(resource)
+	assert.NoError(t, err)
+
+	ja := jsonassert.New(t)
+	ja.Assertf(string(encoded), `{
+		"_templates" : {
+			"default" : {
+				"method" : "PUT",
+				"properties" : null
+			},
+			"delete" : {
+				"method" : "DELETE",
+				"properties" : null
+			}
+		}
+	}`)
+}
+
+func TestDefaultTemplateMissing(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(nil)
+
+	_, ok := resource.DefaultTemplate()
+	assert.False(t, ok)
+
+	resource.AddTemplate("a", gohalforms.Template{})
+	resource.AddTemplate("b", gohalforms.Template{})
+
+	_, ok = resource.DefaultTemplate()
+	assert.False(t, ok)
+}
+
+func TestSelectTemplate(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(nil)
+	resource.AddLink("self", gohalforms.Link{Href: "/users/1"})
+	resource.AddTemplate("search", gohalforms.Template{Target: "/users"})
+	resource.AddTemplate("delete", gohalforms.Template{Method: http.MethodDelete})
+	resource.AddTemplate("rename", gohalforms.Template{Method: http.MethodPatch})
+	resource.SetDefaultTemplate(gohalforms.Template{Method: http.MethodPatch})
+
+	name, template, ok := resource.SelectTemplate("patch", "/users/1")
+	assert.True(t, ok)
+	assert.Equal(t, "default", name)
+	assert.Equal(t, gohalforms.Template{Method: http.MethodPatch}, template)
+
+	name, _, ok = resource.SelectTemplate(http.MethodDelete, "/users/1")
+	assert.True(t, ok)
+	assert.Equal(t, "delete", name)
+
+	name, _, ok = resource.SelectTemplate(http.MethodGet, "/users")
+	assert.True(t, ok)
+	assert.Equal(t, "search", name)
+
+	_, _, ok = resource.SelectTemplate(http.MethodPost, "/users")
+	assert.False(t, ok)
+}
@@ -0,0 +1,326 @@
+package gohalforms
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// uriOperator describes how the expressions of a URI template with a given operator are expanded, as set out in
+// RFC 6570 appendix A.
+type uriOperator struct {
+	// first is written before the first expanded variable.
+	first string
+	// separator is written between expanded variables.
+	separator string
+	// named determines whether each variable is written with its name, as name=value.
+	named bool
+	// ifEmpty is written after the name of a named variable with an empty value.
+	ifEmpty string
+	// reserved determines whether reserved characters are written without being percent-encoded.
+	reserved bool
+	// required determines whether every variable must be provided, because it forms part of the path.
+	required bool
+	// partial determines whether the variables that are not provided can be left in an expression of their own after
+	// those that are, when the result is to remain a URI template.
+	partial bool
+}
+
+// uriOperators are the supported operators, keyed by their character. Simple expressions have no operator.
+var uriOperators = map[byte]uriOperator{
+	0:   {first: "", separator: ",", required: true},
+	'+': {first: "", separator: ",", reserved: true, required: true},
+	'#': {first: "#", separator: ",", reserved: true},
+	'.': {first: ".", separator: ".", partial: true},
+	'/': {first: "/", separator: "/", required: true, partial: true},
+	';': {first: ";", separator: ";", named: true, partial: true},
+	'?': {first: "?", separator: "&", named: true, ifEmpty: "=", partial: true},
+	'&': {first: "&", separator: "&", named: true, ifEmpty: "=", partial: true},
+}
+
+// uriVariable is a single variable within an expression of a URI template, along with its modifiers.
+type uriVariable struct {
+	// spec is the variable as written in the template.
+	spec string
+	name string
+	// explode determines whether each member of a list or associative array is expanded separately.
+	explode bool
+	// prefix, if positive, is the maximum number of characters of a string value to expand.
+	prefix int
+}
+
+// parseURIVariable parses a single variable of an expression, such as "id", "path*" or "name:3".
+func parseURIVariable(spec string) uriVariable {
+	variable := uriVariable{spec: spec, name: spec}
+
+	if strings.HasSuffix(spec, "*") {
+		variable.name = strings.TrimSuffix(spec, "*")
+		variable.explode = true
+	} else if name, prefix, found := strings.Cut(spec, ":"); found {
+		variable.name = name
+		variable.prefix, _ = strconv.Atoi(prefix)
+	}
+
+	return variable
+}
+
+// uriValue is the value of a variable, as either a string, a list, or an associative array of keys and values.
+type uriValue struct {
+	text  string
+	list  []string
+	pairs [][2]string
+}
+
+// newURIValue converts the value of a variable into the forms described by RFC 6570. Slices and arrays become lists,
+// and maps become associative arrays ordered by key. It returns false if the variable is undefined, which includes nil
+// values and empty lists and associative arrays.
+func newURIValue(value any) (uriValue, bool) {
+	if value == nil {
+		return uriValue{}, false
+	}
+
+	reflected := reflect.ValueOf(value)
+
+	switch reflected.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if reflected.IsNil() {
+			return uriValue{}, false
+		}
+
+		return newURIValue(reflected.Elem().Interface())
+	case reflect.Slice, reflect.Array:
+		list := make([]string, 0, reflected.Len())
+		for index := 0; index < reflected.Len(); index++ {
+			list = append(list, fmt.Sprint(reflected.Index(index).Interface()))
+		}
+
+		return uriValue{list: list}, len(list) > 0
+	case reflect.Map:
+		pairs := make([][2]string, 0, reflected.Len())
+		for _, key := range reflected.MapKeys() {
+			pairs = append(pairs, [2]string{fmt.Sprint(key.Interface()), fmt.Sprint(reflected.MapIndex(key).Interface())})
+		}
+
+		sort.Slice(pairs, func(i, j int) bool {
+			return pairs[i][0] < pairs[j][0]
+		})
+
+		return uriValue{pairs: pairs}, len(pairs) > 0
+	default:
+		return uriValue{text: fmt.Sprint(value)}, true
+	}
+}
+
+// expand expands a single defined variable of an expression with this operator.
+func (operator uriOperator) expand(variable uriVariable, value uriValue) string {
+	escape := escapeUnreserved
+	if operator.reserved {
+		escape = escapeReserved
+	}
+
+	named := func(name string, text string) string {
+		switch {
+		case !operator.named:
+			return text
+		case text == "":
+			return name + operator.ifEmpty
+		default:
+			return name + "=" + text
+		}
+	}
+
+	var parts []string
+
+	switch {
+	case value.pairs != nil && variable.explode:
+		for _, pair := range value.pairs {
+			if operator.named {
+				parts = append(parts, named(escape(pair[0]), escape(pair[1])))
+			} else {
+				parts = append(parts, escape(pair[0])+"="+escape(pair[1]))
+			}
+		}
+
+		return strings.Join(parts, operator.separator)
+	case value.pairs != nil:
+		for _, pair := range value.pairs {
+			parts = append(parts, escape(pair[0]), escape(pair[1]))
+		}
+
+		return named(variable.name, strings.Join(parts, ","))
+	case value.list != nil && variable.explode:
+		for _, item := range value.list {
+			parts = append(parts, named(variable.name, escape(item)))
+		}
+
+		return strings.Join(parts, operator.separator)
+	case value.list != nil:
+		for _, item := range value.list {
+			parts = append(parts, escape(item))
+		}
+
+		return named(variable.name, strings.Join(parts, ","))
+	}
+
+	text := value.text
+	if variable.prefix > 0 && utf8.RuneCountInString(text) > variable.prefix {
+		text = string([]rune(text)[:variable.prefix])
+	}
+
+	return named(variable.name, escape(text))
+}
+
+// expandURI expands the expressions of a URI template using the provided variables, following RFC 6570 up to level 4.
+// Every operator is supported, along with the explode and prefix modifiers. Slices and arrays are expanded as lists,
+// and maps as associative arrays ordered by key.
+//
+// Simple {name}, reserved {+name} and path segment {/name} expressions form the path, so they are required, and the
+// names of any whose variables are not provided are returned. Other expressions are optional. When keep is true,
+// expressions with variables that are not provided are left in place so that the result is still a URI template, and
+// templated reports whether this happened. Otherwise they are removed.
+func expandURI(template string, vars map[string]any, keep bool) (uri string, missing []string, templated bool) {
+	var result strings.Builder
+
+	// Whether a query string has already been started, either by the template itself or by an earlier expression.
+	query := false
+
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			break
+		}
+
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			break
+		}
+
+		end += start
+		literal := template[:start]
+		raw := template[start+1 : end]
+		template = template[end+1:]
+
+		result.WriteString(literal)
+		query = query || strings.ContainsRune(literal, '?')
+
+		var character byte
+
+		expression := raw
+		if expression != "" && strings.IndexByte("+#./;?&", expression[0]) >= 0 {
+			character, expression = expression[0], expression[1:]
+		}
+
+		operator := uriOperators[character]
+		queryOperator := character == '?' || character == '&'
+
+		var (
+			expanded   []string
+			unexpanded []string
+		)
+
+		for _, spec := range strings.Split(expression, ",") {
+			variable := parseURIVariable(spec)
+
+			value, defined := newURIValue(vars[variable.name])
+			if !defined {
+				unexpanded = append(unexpanded, variable.spec)
+
+				if operator.required {
+					missing = append(missing, variable.name)
+				}
+
+				continue
+			}
+
+			expanded = append(expanded, operator.expand(variable, value))
+		}
+
+		if keep && len(unexpanded) > 0 && !operator.partial {
+			result.WriteString("{" + raw + "}")
+
+			templated = true
+
+			continue
+		}
+
+		if len(expanded) > 0 {
+			first := operator.first
+			if queryOperator {
+				first = "?"
+				if query {
+					first = "&"
+				}
+
+				query = true
+			}
+
+			result.WriteString(first + strings.Join(expanded, operator.separator))
+		}
+
+		if keep && len(unexpanded) > 0 {
+			remaining := string(character)
+			if queryOperator {
+				remaining = "?"
+				if query {
+					remaining = "&"
+				}
+			}
+
+			result.WriteString("{" + remaining + strings.Join(unexpanded, ",") + "}")
+
+			templated = true
+		}
+	}
+
+	result.WriteString(template)
+
+	return result.String(), missing, templated
+}
+
+// escapeUnreserved percent-encodes every character of a value other than the unreserved characters of RFC 3986.
+func escapeUnreserved(value string) string {
+	return escapeURI(value, false)
+}
+
+// escapeReserved percent-encodes every character of a value other than the unreserved and reserved characters of
+// RFC 3986, leaving any existing percent-encoded triplets alone.
+func escapeReserved(value string) string {
+	return escapeURI(value, true)
+}
+
+// escapeURI percent-encodes a value, optionally allowing reserved characters and existing percent-encoded triplets.
+func escapeURI(value string, reserved bool) string {
+	const hex = "0123456789ABCDEF"
+
+	var result strings.Builder
+
+	for index := 0; index < len(value); index++ {
+		character := value[index]
+
+		switch {
+		case 'a' <= character && character <= 'z',
+			'A' <= character && character <= 'Z',
+			'0' <= character && character <= '9',
+			character == '-', character == '.', character == '_', character == '~':
+			result.WriteByte(character)
+		case reserved && strings.IndexByte(":/?#[]@!$&'()*+,;=", character) >= 0:
+			result.WriteByte(character)
+		case reserved && character == '%' && index+2 < len(value) && isHex(value[index+1]) && isHex(value[index+2]):
+			result.WriteByte(character)
+		default:
+			result.WriteByte('%')
+			result.WriteByte(hex[character>>4])
+			result.WriteByte(hex[character&0x0f])
+		}
+	}
+
+	return result.String()
+}
+
+// isHex determines whether a character is a hexadecimal digit.
+func isHex(character byte) bool {
+	return '0' <= character && character <= '9' || 'a' <= character && character <= 'f' || 'A' <= character && character <= 'F'
+}
@@ -0,0 +1,201 @@
+package gohalforms
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Issue describes a problem with a resource that makes it invalid according to the HAL or HAL-FORMS specifications.
+type Issue struct {
+	// Location is a JSON Pointer to the part of the encoded resource that the issue relates to.
+	Location string
+	// Message is a human-readable description of the issue.
+	Message string
+}
+
+// String returns a description of the issue, including its location.
+func (issue Issue) String() string {
+	return fmt.Sprintf("%s: %s", issue.Location, issue.Message)
+}
+
+// reporter records an issue found at a location within the document.
+type reporter func(location string, format string, args ...any)
+
+// knownMethods are the values that are recognised for Template.Method.
+var knownMethods = map[string]bool{
+	http.MethodConnect: true,
+	http.MethodDelete:  true,
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodPatch:   true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodTrace:   true,
+}
+
+// Validate checks the structure of the resource against the HAL and HAL-FORMS specifications, recursing into any
+// embedded resources. Embedded resources added from streams are not checked, since that would consume the streams.
+//
+// Returns:
+//
+//	The issues that were found, or an empty slice if the resource is valid.
+//
+// Example:
+//
+//	// Check that a handler produces a valid resource.
+//	halResource := buildUserResource(user)
+//	assert.Empty(t, halResource.Validate())
+func (resource Resource) Validate() []Issue {
+	return resource.validate("")
+}
+
+// validate checks the resource found at the given location within the document.
+func (resource Resource) validate(location string) []Issue {
+	issues := []Issue{}
+
+	report := func(location string, format string, args ...any) {
+		issues = append(issues, Issue{Location: location, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, rel := range resource.links.keys(false) {
+		values := resource.links.get(rel)
+
+		for index, link := range values {
+			linkLocation := location + "/_links/" + escapePointer(rel)
+			if len(values) > 1 {
+				linkLocation += "/" + strconv.Itoa(index)
+			}
+
+			link.validate(linkLocation, report)
+		}
+	}
+
+	templates := resource.encodedTemplates()
+
+	for _, name := range templates.keys(false) {
+		templates.get(name).validate(location+"/_templates/"+escapePointer(name), report)
+	}
+
+	for _, rel := range resource.embedded.keys(false) {
+		values := resource.embedded.get(rel)
+
+		for index, value := range values.items {
+			issues = append(issues, value.validate(values.location(location+"/_embedded/"+escapePointer(rel), index))...)
+		}
+	}
+
+	return issues
+}
+
+// validate checks a single link found at the given location within the document.
+func (link Link) validate(location string, report reporter) {
+	if link.Href == "" {
+		report(location+"/href", "link is missing an href")
+	}
+
+	hasTemplateSyntax := strings.ContainsAny(link.Href, "{}")
+
+	if link.Templated && !hasTemplateSyntax {
+		report(location+"/templated", "link is marked as templated but the href %q has no template expressions", link.Href)
+	} else if !link.Templated && hasTemplateSyntax {
+		report(location+"/templated", "link href %q has template expressions but is not marked as templated", link.Href)
+	}
+}
+
+// validate checks a single template found at the given location within the document.
+func (template Template) validate(location string, report reporter) {
+	if template.Method != "" && !knownMethods[template.Method] {
+		report(location+"/method", "unknown method %q", template.Method)
+	}
+
+	names := map[string]bool{}
+
+	for index, property := range template.Properties {
+		propertyLocation := location + "/properties/" + strconv.Itoa(index)
+
+		if names[property.Name] {
+			report(propertyLocation+"/name", "duplicate property name %q", property.Name)
+		}
+
+		names[property.Name] = true
+
+		property.validate(propertyLocation, report)
+	}
+}
+
+// validate checks a single template property found at the given location within the document.
+func (property Property) validate(location string, report reporter) {
+	if property.Name == "" {
+		report(location+"/name", "property is missing a name")
+	}
+
+	if property.Type != "" && !property.Type.Known() {
+		report(location+"/type", "unknown property type %q", property.Type)
+	}
+
+	property.validateBound(location+"/min", "min", property.Min, report)
+	property.validateBound(location+"/max", "max", property.Max, report)
+
+	if property.Min != nil && property.Max != nil {
+		if order, ok := property.Min.Compare(*property.Max); !ok {
+			report(location+"/min", "min of %s and max of %s are different kinds of bound", property.Min, property.Max)
+		} else if order > 0 {
+			report(location+"/min", "min of %s is greater than max of %s", property.Min, property.Max)
+		}
+	}
+
+	if property.Step < 0 {
+		report(location+"/step", "step of %v is negative", property.Step)
+	}
+
+	if property.MaxLength > 0 && property.MinLength > property.MaxLength {
+		report(location+"/minLength", "minLength of %d is greater than maxLength of %d", property.MinLength, property.MaxLength)
+	}
+
+	if property.Regex != "" {
+		if _, err := regexp.Compile(property.Regex); err != nil {
+			report(location+"/regex", "invalid regex: %v", err)
+		}
+	}
+
+	switch options := property.Options.(type) {
+	case InlineOption:
+		validateItems(location+"/options", options.MinItems, options.MaxItems, report)
+
+		for index, selected := range options.SelectedValues {
+			if !options.contains(selected) {
+				report(location+"/options/selectedValues/"+strconv.Itoa(index), "selected value %q is not one of the inline options", selected)
+			}
+		}
+	case LinkOption:
+		validateItems(location+"/options", options.MinItems, options.MaxItems, report)
+		options.Link.validate(location+"/options/link", report)
+	}
+}
+
+// validateBound checks that a bound suits the type of the property that it constrains.
+func (property Property) validateBound(location string, name string, bound *Bound, report reporter) {
+	if bound == nil {
+		return
+	}
+
+	inputType := property.Type
+	if inputType == "" {
+		inputType = InputText
+	}
+
+	if !bound.Suits(inputType) {
+		report(location, "%s of %s cannot be used with a property of type %q", name, bound, inputType)
+	}
+}
+
+// validateItems checks the number of items allowed by a property option.
+func validateItems(location string, minItems uint32, maxItems uint32, report reporter) {
+	if maxItems > 0 && minItems > maxItems {
+		report(location+"/minItems", "minItems of %d is greater than maxItems of %d", minItems, maxItems)
+	}
+}
@@ -0,0 +1,81 @@
+package gohalforms_test
+
+import (
+	"testing"
+
+	"github.com/sazzer/gohalforms"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateValidResource(t *testing.T) {
+	t.Parallel()
+
+	resource := gohalforms.NewResource(map[string]any{"hello": "World!"})
+	resource.AddLink("self", gohalforms.Link{Href: "/testSelfLink"})
+	resource.AddLink("search", gohalforms.Link{Href: "/search{?q}", Templated: true})
+	resource.AddTemplate("default", gohalforms.Template{
+		Method: "POST",
+		Properties: []gohalforms.Property{
+			{Name: "title", Type: gohalforms.InputText, MinLength: 1, MaxLength: 10, Regex: "[a-z]+"},
+			{Name: "age", Type: gohalforms.InputNumber, Min: gohalforms.NumberBound(-1.5), Max: gohalforms.NumberBound(10), Step: 0.5},
+			{
+				Name: "colour",
+				Options: gohalforms.InlineOption{
+					Inline:         []gohalforms.InlineOptionValue{{Prompt: "Red", Value: "red"}},
+					SelectedValues: []string{"red"},
+				},
+			},
+		},
+	})
+	resource.AddEmbedded("other", gohalforms.NewResource(nil))
+
+	assert.Empty(t, resource.Validate())
+}
+
+func TestValidateInvalidResource(t *testing.T) {
+	t.Parallel()
+
+	embedded := gohalforms.NewResource(nil)
+	embedded.AddLink("self", gohalforms.Link{Href: "/a"})
+	embedded.AddLink("self", gohalforms.Link{})
+
+	resource := gohalforms.NewResource(nil)
+	resource.AddLink("templated", gohalforms.Link{Href: "/search", Templated: true})
+	resource.AddLink("untemplated", gohalforms.Link{Href: "/search{?q}"})
+	resource.AddTemplate("create", gohalforms.Template{
+		Method: "SEND",
+		Properties: []gohalforms.Property{
+			{Name: "title", Type: "word", MinLength: 10, MaxLength: 1, Regex: "[a-z"},
+			{Name: "title", Type: gohalforms.InputNumber, Min: gohalforms.NumberBound(10), Max: gohalforms.NumberBound(1)},
+			{
+				Options: gohalforms.InlineOption{
+					Inline:         []gohalforms.InlineOptionValue{{Prompt: "Red", Value: "red"}},
+					SelectedValues: []string{"red", "blue"},
+					MinItems:       2,
+					MaxItems:       1,
+				},
+			},
+			{
+				Name:    "link",
+				Options: gohalforms.LinkOption{},
+			},
+		},
+	})
+	resource.AddEmbedded("other", embedded)
+
+	assert.Equal(t, []gohalforms.Issue{
+		{Location: "/_links/templated/templated", Message: `link is marked as templated but the href "/search" has no template expressions`},
+		{Location: "/_links/untemplated/templated", Message: `link href "/search{?q}" has template expressions but is not marked as templated`},
+		{Location: "/_templates/default/method", Message: `unknown method "SEND"`},
+		{Location: "/_templates/default/properties/0/type", Message: `unknown property type "word"`},
+		{Location: "/_templates/default/properties/0/minLength", Message: "minLength of 10 is greater than maxLength of 1"},
+		{Location: "/_templates/default/properties/0/regex", Message: "invalid regex: error parsing regexp: missing closing ]: `[a-z`"},
+		{Location: "/_templates/default/properties/1/name", Message: `duplicate property name "title"`},
+		{Location: "/_templates/default/properties/1/min", Message: "min of 10 is greater than max of 1"},
+		{Location: "/_templates/default/properties/2/name", Message: "property is missing a name"},
+		{Location: "/_templates/default/properties/2/options/minItems", Message: "minItems of 2 is greater than maxItems of 1"},
+		{Location: "/_templates/default/properties/2/options/selectedValues/1", Message: `selected value "blue" is not one of the inline options`},
+		{Location: "/_templates/default/properties/3/options/link/href", Message: "link is missing an href"},
+		{Location: "/_embedded/other/_links/self/1/href", Message: "link is missing an href"},
+	}, resource.Validate())
+}